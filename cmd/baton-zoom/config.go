@@ -2,6 +2,8 @@ package main
 
 import (
 	"github.com/conductorone/baton-sdk/pkg/field"
+	"github.com/conductorone/baton-zoom/pkg/connector"
+	"github.com/conductorone/baton-zoom/pkg/zoom"
 )
 
 var (
@@ -20,9 +22,122 @@ var (
 		field.WithRequired(true),
 		field.WithDescription("Client Secret used to generate token providing access to Zoom API."),
 	)
+	MaxRetriesField = field.IntField(
+		"zoom-max-retries",
+		field.WithDescription("Number of times a rate limited (429) or failed (5xx) Zoom API request is retried."),
+		field.WithDefaultValue(3),
+	)
+	MaxRetryDelayField = field.IntField(
+		"zoom-max-retry-delay",
+		field.WithDescription("Longest time in seconds to wait before retrying a Zoom API request. Longer Retry-After values fail the request instead."),
+		field.WithDefaultValue(60),
+	)
+	PlanField = field.SelectField(
+		"zoom-plan",
+		zoom.PlanTiers,
+		field.WithDescription("Zoom plan of the account, used to pick the default per-second rate limits: pro, business or enterprise."),
+		field.WithDefaultValue(string(zoom.PlanPro)),
+	)
+	RateLimitsField = field.StringField(
+		"zoom-rate-limits",
+		field.WithDescription("Overrides the plan's requests per second per Zoom API category, e.g. \"light=20,medium=10,heavy=5\". Zero disables throttling for a category."),
+	)
+	EnvironmentField = field.SelectField(
+		"zoom-environment",
+		zoom.Environments,
+		field.WithDescription("Zoom cloud the account lives in: commercial or gov (ZoomGov)."),
+		field.WithDefaultValue(string(zoom.EnvironmentCommercial)),
+	)
+	APIBaseURLField = field.StringField(
+		"zoom-api-base-url",
+		field.WithDescription("Overrides the Zoom API base URL of the environment, e.g. https://api.zoomgov.com/v2."),
+	)
+	OAuthURLField = field.StringField(
+		"zoom-oauth-url",
+		field.WithDescription("Overrides the Zoom OAuth token URL of the environment, e.g. https://zoomgov.com/oauth/token."),
+	)
+	PageSizeField = field.IntField(
+		"zoom-page-size",
+		field.WithDescription("Number of records requested per page from Zoom list endpoints, at most 300."),
+		field.WithDefaultValue(zoom.DefaultPageSize),
+	)
+	DefaultRoleField = field.StringField(
+		"zoom-default-role",
+		field.WithDescription("Name or ID of the role users are moved to when their role is revoked."),
+		field.WithDefaultValue("Member"),
+	)
+	ForceDeleteRolesField = field.BoolField(
+		"zoom-force-delete-roles",
+		field.WithDescription("Delete custom roles that still have members, moving the members to the default role first. Refused when a member is protected."),
+	)
+	ProtectedPrincipalsField = field.StringSliceField(
+		"zoom-protected-principals",
+		field.WithDescription("User IDs or emails that are never deleted or have access revoked. Owners and the account owner are always protected."),
+	)
+	UserStatusesField = field.StringSliceField(
+		"zoom-user-statuses",
+		field.WithDescription("Statuses of the users to sync: active, inactive and pending."),
+		field.WithDefaultValue(zoom.UserStatuses),
+	)
+	UserDetailsField = field.BoolField(
+		"zoom-user-details",
+		field.WithDescription("Look up every user on its own for the job title, manager, location and login types. Costs one Zoom API request per user and sync."),
+	)
+	DeprovisionModeField = field.SelectField(
+		"zoom-deprovision-mode",
+		connector.DeprovisionModes,
+		field.WithDescription("How deleted users are deprovisioned: deactivate, disassociate or delete."),
+		field.WithDefaultValue(connector.DeprovisionDisassociate),
+	)
+	TransferEmailField = field.StringField(
+		"zoom-transfer-email",
+		field.WithDescription("Email of the user that receives the content of disassociated or deleted users, or \"manager\" for each user's manager."),
+	)
+	TransferContentField = field.StringSliceField(
+		"zoom-transfer-content",
+		field.WithDescription("Content to transfer to --zoom-transfer-email: meetings, webinars, recordings and whiteboards."),
+	)
+	ResendInvitesField = field.BoolField(
+		"zoom-resend-invites",
+		field.WithDescription("When a created account already exists with a pending invitation, cancel it and invite the user again."),
+	)
+	DryRunField = field.BoolField(
+		"dry-run",
+		field.WithDescription("Log the Zoom requests provisioning would send instead of sending them."),
+	)
+	RecordDirField = field.StringField(
+		"zoom-record-dir",
+		field.WithDescription("Directory to save sanitized Zoom API requests and responses to, for building offline fixtures."),
+		field.WithHidden(true),
+	)
+	ReplayDirField = field.StringField(
+		"zoom-replay-dir",
+		field.WithDescription("Directory of responses saved with --zoom-record-dir to serve instead of calling the Zoom API."),
+		field.WithHidden(true),
+	)
 	ConfigurationFields = []field.SchemaField{
 		AccountIdField,
 		ZoomClientIdField,
 		ZoomClientSecretField,
+		MaxRetriesField,
+		MaxRetryDelayField,
+		PlanField,
+		RateLimitsField,
+		EnvironmentField,
+		APIBaseURLField,
+		OAuthURLField,
+		PageSizeField,
+		DefaultRoleField,
+		ForceDeleteRolesField,
+		ProtectedPrincipalsField,
+		UserStatusesField,
+		UserDetailsField,
+		DeprovisionModeField,
+		TransferEmailField,
+		TransferContentField,
+		ResendInvitesField,
+		DryRunField,
+		RecordDirField,
+		ReplayDirField,
 	}
 )
@@ -40,6 +40,16 @@ func TestConfigs(t *testing.T) {
 				true,
 				"all",
 			},
+			{
+				"--account-id 1 --zoom-client-id 1 --zoom-client-secret 1 --zoom-environment gov",
+				true,
+				"gov environment",
+			},
+			{
+				"--account-id 1 --zoom-client-id 1 --zoom-client-secret 1 --zoom-environment moon",
+				false,
+				"unknown environment",
+			},
 		},
 	)
 }
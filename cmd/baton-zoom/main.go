@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	configSchema "github.com/conductorone/baton-sdk/pkg/config"
 	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
 	"github.com/conductorone/baton-sdk/pkg/field"
 	"github.com/conductorone/baton-sdk/pkg/types"
 	"github.com/conductorone/baton-zoom/pkg/connector"
+	"github.com/conductorone/baton-zoom/pkg/zoom"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
@@ -43,11 +45,56 @@ func main() {
 func getConnector(ctx context.Context, v *viper.Viper) (types.ConnectorServer, error) {
 	l := ctxzap.Extract(ctx)
 
+	retryPolicy := zoom.DefaultRetryPolicy()
+	retryPolicy.MaxRetries = v.GetInt(MaxRetriesField.FieldName)
+	retryPolicy.MaxDelay = time.Duration(v.GetInt(MaxRetryDelayField.FieldName)) * time.Second
+
+	rateLimits, err := zoom.DefaultRateLimits(zoom.PlanTier(v.GetString(PlanField.FieldName)))
+	if err != nil {
+		l.Error("error parsing zoom plan", zap.Error(err))
+		return nil, err
+	}
+
+	rateLimitOverrides, err := zoom.ParseRateLimits(v.GetString(RateLimitsField.FieldName))
+	if err != nil {
+		l.Error("error parsing zoom rate limits", zap.Error(err))
+		return nil, err
+	}
+
+	endpoints, err := zoom.EndpointsFor(zoom.Environment(v.GetString(EnvironmentField.FieldName)))
+	if err != nil {
+		l.Error("error parsing zoom environment", zap.Error(err))
+		return nil, err
+	}
+
+	endpoints, err = endpoints.WithOverrides(v.GetString(APIBaseURLField.FieldName), v.GetString(OAuthURLField.FieldName))
+	if err != nil {
+		l.Error("error parsing zoom endpoints", zap.Error(err))
+		return nil, err
+	}
+
 	cb, err := connector.New(
 		ctx,
 		v.GetString(AccountIdField.FieldName),
 		v.GetString(ZoomClientIdField.FieldName),
 		v.GetString(ZoomClientSecretField.FieldName),
+		connector.WithEndpoints(endpoints),
+		connector.WithDefaultRole(v.GetString(DefaultRoleField.FieldName)),
+		connector.WithForceDeleteRoles(v.GetBool(ForceDeleteRolesField.FieldName)),
+		connector.WithUserStatuses(v.GetStringSlice(UserStatusesField.FieldName)...),
+		connector.WithUserDetails(v.GetBool(UserDetailsField.FieldName)),
+		connector.WithDeprovisionMode(v.GetString(DeprovisionModeField.FieldName)),
+		connector.WithTransfer(v.GetString(TransferEmailField.FieldName), v.GetStringSlice(TransferContentField.FieldName)...),
+		connector.WithResendInvites(v.GetBool(ResendInvitesField.FieldName)),
+		connector.WithDryRun(v.GetBool(DryRunField.FieldName)),
+		connector.WithProtectedPrincipals(v.GetStringSlice(ProtectedPrincipalsField.FieldName)...),
+		connector.WithRecordDir(v.GetString(RecordDirField.FieldName)),
+		connector.WithReplayDir(v.GetString(ReplayDirField.FieldName)),
+		connector.WithClientOptions(
+			zoom.WithRetryPolicy(retryPolicy),
+			zoom.WithRateLimits(rateLimits.Merge(rateLimitOverrides)),
+			zoom.WithPageSize(v.GetInt(PageSizeField.FieldName)),
+		),
 	)
 	if err != nil {
 		l.Error("error creating connector", zap.Error(err))
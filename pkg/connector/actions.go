@@ -0,0 +1,229 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	configv1 "github.com/conductorone/baton-sdk/pb/c1/config/v1"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/actions"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-zoom/pkg/zoom"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	disableUserAction = "disable_user"
+	enableUserAction  = "enable_user"
+	signOutUserAction = "sign_out_user"
+
+	userIdArgument = "user_id"
+	statusResult   = "status"
+	revokedResult  = "revoked"
+
+	// revokedSSOToken is what the sign out action reports as revoked. Zoom documents that revoking the SSO
+	// token signs the user out, but doesn't say which sessions were open.
+	revokedSSOToken = "sso_token"
+)
+
+var (
+	userIdField = &configv1.Field{
+		Name:        userIdArgument,
+		DisplayName: "User ID",
+		Description: "ID of the Zoom user resource.",
+		Field:       &configv1.Field_StringField{},
+		IsRequired:  true,
+	}
+	userStatusFields = []*configv1.Field{
+		{
+			Name:        userIdArgument,
+			DisplayName: "User ID",
+			Field:       &configv1.Field_StringField{},
+		},
+		{
+			Name:        statusResult,
+			DisplayName: "Status",
+			Description: "Status of the user after the action: active, inactive or pending.",
+			Field:       &configv1.Field_StringField{},
+		},
+	}
+
+	disableUserSchema = &v2.BatonActionSchema{
+		Name:        disableUserAction,
+		DisplayName: "Disable User",
+		Description: "Deactivate a Zoom user, signing them out and blocking sign in without deleting them.",
+		Arguments:   []*configv1.Field{userIdField},
+		ReturnTypes: userStatusFields,
+	}
+	enableUserSchema = &v2.BatonActionSchema{
+		Name:        enableUserAction,
+		DisplayName: "Enable User",
+		Description: "Activate a deactivated Zoom user.",
+		Arguments:   []*configv1.Field{userIdField},
+		ReturnTypes: userStatusFields,
+	}
+	signOutUserSchema = &v2.BatonActionSchema{
+		Name:        signOutUserAction,
+		DisplayName: "Sign Out User",
+		Description: "Revoke a Zoom user's SSO token, which Zoom documents as signing them out.",
+		Arguments:   []*configv1.Field{userIdField},
+		ReturnTypes: []*configv1.Field{
+			{
+				Name:        userIdArgument,
+				DisplayName: "User ID",
+				Field:       &configv1.Field_StringField{},
+			},
+			{
+				Name:        revokedResult,
+				DisplayName: "Revoked",
+				Description: "What was revoked: sso_token, empty in dry-run mode.",
+				Field:       &configv1.Field_StringSliceField{},
+			},
+		},
+	}
+)
+
+// RegisterActionManager offers the custom actions that change users outside of grants.
+func (z *Zoom) RegisterActionManager(ctx context.Context) (connectorbuilder.CustomActionManager, error) {
+	protected := newProtectedPrincipals(z.client, z.protected)
+
+	return newActionManager(ctx, z.client, protected)
+}
+
+func newActionManager(ctx context.Context, client userActionAPI, protected *protectedPrincipals) (*actions.ActionManager, error) {
+	manager := actions.NewActionManager(ctx)
+
+	statuses := &userStatusActions{client: client, protected: protected}
+	err := manager.RegisterAction(ctx, disableUserAction, disableUserSchema, statuses.disable)
+	if err != nil {
+		return nil, fmt.Errorf("baton-zoom: failed to register %s action: %w", disableUserAction, err)
+	}
+	err = manager.RegisterAction(ctx, enableUserAction, enableUserSchema, statuses.enable)
+	if err != nil {
+		return nil, fmt.Errorf("baton-zoom: failed to register %s action: %w", enableUserAction, err)
+	}
+
+	sessions := &signOutAction{client: client}
+	err = manager.RegisterAction(ctx, signOutUserAction, signOutUserSchema, sessions.signOut)
+	if err != nil {
+		return nil, fmt.Errorf("baton-zoom: failed to register %s action: %w", signOutUserAction, err)
+	}
+
+	return manager, nil
+}
+
+// userStatusActions activate and deactivate users through PUT /users/{userId}/status.
+type userStatusActions struct {
+	client    userStatusAPI
+	protected *protectedPrincipals
+}
+
+func (a *userStatusActions) disable(ctx context.Context, args *structpb.Struct) (*structpb.Struct, annotations.Annotations, error) {
+	userId, err := userIdArg(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = a.protected.check(ctx, userId, "disable user")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return a.setStatus(ctx, userId, zoom.DeactivateUser, zoom.UserStatusInactive)
+}
+
+func (a *userStatusActions) enable(ctx context.Context, args *structpb.Struct) (*structpb.Struct, annotations.Annotations, error) {
+	userId, err := userIdArg(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return a.setStatus(ctx, userId, zoom.ActivateUser, zoom.UserStatusActive)
+}
+
+// setStatus applies action unless the user already has the wanted status, then confirms the change.
+func (a *userStatusActions) setStatus(ctx context.Context, userId string, action string, want string) (*structpb.Struct, annotations.Annotations, error) {
+	user, _, err := a.client.GetUser(ctx, userId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.Status == want {
+		return userStatusResult(userId, user.Status), nil, nil
+	}
+	if user.Status == zoom.UserStatusPending {
+		return nil, nil, status.Errorf(codes.FailedPrecondition, "baton-zoom: user %s hasn't accepted their invitation yet", userId)
+	}
+
+	err = a.client.UpdateUserStatus(ctx, userId, action)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-zoom: failed to %s user %s: %w", action, userId, err)
+	}
+	if a.client.DryRun() {
+		return userStatusResult(userId, user.Status), withMarkers(nil, dryRunMarker), nil
+	}
+
+	user, _, err = a.client.GetUser(ctx, userId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-zoom: failed to confirm user %s status: %w", userId, err)
+	}
+	if user.Status != want {
+		return nil, nil, fmt.Errorf("baton-zoom: user %s is %s after the %s", userId, user.Status, action)
+	}
+
+	return userStatusResult(userId, user.Status), nil, nil
+}
+
+// signOutAction revokes a user's SSO token, e.g. when their device is lost. Only DELETE /users/{userId}/token
+// is called, the Zoom API has no separate sign out endpoint to call as well.
+type signOutAction struct {
+	client sessionAPI
+}
+
+func (a *signOutAction) signOut(ctx context.Context, args *structpb.Struct) (*structpb.Struct, annotations.Annotations, error) {
+	userId, err := userIdArg(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = a.client.RevokeSSOToken(ctx, userId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-zoom: failed to sign out user %s: %w", userId, err)
+	}
+
+	revoked := &structpb.ListValue{}
+	var annos annotations.Annotations
+	if a.client.DryRun() {
+		annos = withMarkers(annos, dryRunMarker)
+	} else {
+		revoked.Values = []*structpb.Value{structpb.NewStringValue(revokedSSOToken)}
+	}
+
+	return &structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			userIdArgument: structpb.NewStringValue(userId),
+			revokedResult:  structpb.NewListValue(revoked),
+		},
+	}, annos, nil
+}
+
+func userIdArg(args *structpb.Struct) (string, error) {
+	userId := strings.TrimSpace(args.GetFields()[userIdArgument].GetStringValue())
+	if userId == "" {
+		return "", status.Errorf(codes.InvalidArgument, "baton-zoom: %s is required", userIdArgument)
+	}
+
+	return userId, nil
+}
+
+func userStatusResult(userId string, userStatus string) *structpb.Struct {
+	return &structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			userIdArgument: structpb.NewStringValue(userId),
+			statusResult:   structpb.NewStringValue(userStatus),
+		},
+	}
+}
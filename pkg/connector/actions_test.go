@@ -0,0 +1,164 @@
+package connector
+
+import (
+	"testing"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-zoom/pkg/zoom"
+	"github.com/conductorone/baton-zoom/pkg/zoom/zoomtest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func userIdArgs(t *testing.T, userId string) *structpb.Struct {
+	t.Helper()
+
+	args, err := structpb.NewStruct(map[string]interface{}{userIdArgument: userId})
+	require.NoError(t, err)
+
+	return args
+}
+
+func TestUserActionsAgainstFakeZoom(t *testing.T) {
+	srv := zoomtest.NewServer()
+	defer srv.Close()
+
+	srv.AddUser(zoom.User{Email: "owner@example.com", RoleID: zoomtest.OwnerRoleID})
+	jane := srv.AddUser(zoom.User{Email: "jane@example.com"})
+
+	z := newTestConnector(t, srv)
+	manager, err := z.RegisterActionManager(ctx)
+	require.NoError(t, err)
+
+	schemas, _, err := manager.ListActionSchemas(ctx)
+	require.NoError(t, err)
+	var names []string
+	for _, schema := range schemas {
+		names = append(names, schema.Name)
+	}
+	require.ElementsMatch(t, []string{disableUserAction, enableUserAction, signOutUserAction}, names)
+
+	_, actionStatus, rv, _, err := manager.InvokeAction(ctx, disableUserAction, userIdArgs(t, jane.ID))
+	require.NoError(t, err)
+	require.Equal(t, v2.BatonActionStatus_BATON_ACTION_STATUS_COMPLETE, actionStatus)
+	require.Equal(t, zoom.UserStatusInactive, rv.Fields[statusResult].GetStringValue())
+	got, _ := srv.User(jane.ID)
+	require.Equal(t, zoom.UserStatusInactive, got.Status)
+
+	_, actionStatus, rv, _, err = manager.InvokeAction(ctx, enableUserAction, userIdArgs(t, jane.ID))
+	require.NoError(t, err)
+	require.Equal(t, v2.BatonActionStatus_BATON_ACTION_STATUS_COMPLETE, actionStatus)
+	require.Equal(t, zoom.UserStatusActive, rv.Fields[statusResult].GetStringValue())
+	got, _ = srv.User(jane.ID)
+	require.Equal(t, zoom.UserStatusActive, got.Status)
+
+	_, actionStatus, rv, _, err = manager.InvokeAction(ctx, signOutUserAction, userIdArgs(t, jane.ID))
+	require.NoError(t, err)
+	require.Equal(t, v2.BatonActionStatus_BATON_ACTION_STATUS_COMPLETE, actionStatus)
+	require.Equal(t, []interface{}{revokedSSOToken}, rv.Fields[revokedResult].GetListValue().AsSlice())
+	require.Contains(t, srv.Requests(), "DELETE /v2/users/"+jane.ID+"/token")
+}
+
+func TestSignOutUser(t *testing.T) {
+	api := &fakeAPI{users: map[string]zoom.User{"user-1": {ID: "user-1"}}}
+	action := &signOutAction{client: api}
+
+	rv, _, err := action.signOut(ctx, userIdArgs(t, "user-1"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"RevokeSSOToken user-1"}, api.calls)
+	require.Equal(t, []interface{}{revokedSSOToken}, rv.Fields[revokedResult].GetListValue().AsSlice())
+
+	_, _, err = action.signOut(ctx, userIdArgs(t, "unknown"))
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestUserStatusActions(t *testing.T) {
+	tests := []struct {
+		name       string
+		disable    bool
+		user       zoom.User
+		userId     string
+		keep       bool
+		wantCalls  []string
+		wantStatus string
+		wantCode   codes.Code
+		wantErr    bool
+	}{
+		{
+			name:       "disable",
+			disable:    true,
+			user:       zoom.User{Status: zoom.UserStatusActive},
+			wantCalls:  []string{"UpdateUserStatus user-1 deactivate"},
+			wantStatus: zoom.UserStatusInactive,
+		},
+		{
+			name:       "enable",
+			user:       zoom.User{Status: zoom.UserStatusInactive},
+			wantCalls:  []string{"UpdateUserStatus user-1 activate"},
+			wantStatus: zoom.UserStatusActive,
+		},
+		{
+			name:       "already disabled",
+			disable:    true,
+			user:       zoom.User{Status: zoom.UserStatusInactive},
+			wantStatus: zoom.UserStatusInactive,
+		},
+		{
+			name:     "pending",
+			user:     zoom.User{Status: zoom.UserStatusPending},
+			wantCode: codes.FailedPrecondition,
+			wantErr:  true,
+		},
+		{
+			name:     "owner",
+			disable:  true,
+			user:     zoom.User{Status: zoom.UserStatusActive, RoleID: zoom.OwnerRoleID},
+			wantCode: codes.PermissionDenied,
+			wantErr:  true,
+		},
+		{
+			name:     "missing user ID",
+			user:     zoom.User{Status: zoom.UserStatusActive},
+			userId:   " ",
+			wantCode: codes.InvalidArgument,
+			wantErr:  true,
+		},
+		{
+			name:    "not changed",
+			disable: true,
+			user:    zoom.User{Status: zoom.UserStatusActive},
+			keep:    true,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.user.ID = "user-1"
+			if tt.userId == "" {
+				tt.userId = tt.user.ID
+			}
+			api := &fakeAPI{users: map[string]zoom.User{"user-1": tt.user}, keepDeletedUsers: tt.keep}
+			actions := &userStatusActions{client: api, protected: newProtectedPrincipals(api, nil)}
+
+			handler := actions.enable
+			if tt.disable {
+				handler = actions.disable
+			}
+			rv, _, err := handler(ctx, userIdArgs(t, tt.userId))
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.wantCode != codes.OK {
+					require.Equal(t, tt.wantCode, status.Code(err))
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCalls, api.calls)
+			require.Equal(t, tt.wantStatus, rv.Fields[statusResult].GetStringValue())
+			require.Equal(t, tt.wantStatus, api.users["user-1"].Status)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package connector
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/conductorone/baton-zoom/pkg/zoom"
+)
+
+// The resource types only depend on the parts of the Zoom API they use,
+// so they can be tested against a fake instead of a live account.
+
+// dryRunner tells whether the client only logs changes, in which case they can't be confirmed.
+type dryRunner interface {
+	DryRun() bool
+}
+
+type userLister interface {
+	GetUsers(ctx context.Context, status string, nextToken string) (*zoom.Page[zoom.User], error)
+}
+
+type userAPI interface {
+	dryRunner
+	userLister
+	GetUser(ctx context.Context, userId string) (zoom.User, *http.Response, error)
+	CreateUser(ctx context.Context, newUser *zoom.UserCreationBody) (*zoom.UserCreationResponse, error)
+	DeleteUser(ctx context.Context, userId string, opts zoom.DeleteUserOptions) error
+	UpdateUserStatus(ctx context.Context, userId string, action string) error
+	// Used to set up new accounts.
+	UpdateUser(ctx context.Context, userId string, update *zoom.UserUpdateBody) error
+	GetGroups(ctx context.Context, nextToken string) (*zoom.Page[zoom.Group], error)
+	AddGroupMembers(ctx context.Context, groupId, userId string) error
+	GetRoles(ctx context.Context, roleType string, nextToken string) (*zoom.Page[zoom.Role], error)
+	AssignRole(ctx context.Context, roleId, userId string) error
+}
+
+// userStatusAPI activates and deactivates users for the custom actions.
+type userStatusAPI interface {
+	dryRunner
+	GetUser(ctx context.Context, userId string) (zoom.User, *http.Response, error)
+	UpdateUserStatus(ctx context.Context, userId string, action string) error
+}
+
+// sessionAPI ends the Zoom sessions of users for the sign out action.
+type sessionAPI interface {
+	dryRunner
+	RevokeSSOToken(ctx context.Context, userId string) error
+}
+
+// userActionAPI is everything the custom actions use.
+type userActionAPI interface {
+	userStatusAPI
+	sessionAPI
+}
+
+type groupAPI interface {
+	dryRunner
+	GetUser(ctx context.Context, userId string) (zoom.User, *http.Response, error)
+	GetGroups(ctx context.Context, nextToken string) (*zoom.Page[zoom.Group], error)
+	GetGroupMembers(ctx context.Context, groupId string) ([]zoom.User, error)
+	GetGroupAdmins(ctx context.Context, groupId string) ([]zoom.User, error)
+	AddGroupMembers(ctx context.Context, groupId, userId string) error
+	AddGroupAdmins(ctx context.Context, groupId, userId string) error
+	DeleteGroupMember(ctx context.Context, groupId, userId string) error
+	DeleteGroupAdmin(ctx context.Context, groupId, userId string) error
+}
+
+type roleAPI interface {
+	dryRunner
+	GetUser(ctx context.Context, userId string) (zoom.User, *http.Response, error)
+	GetRoles(ctx context.Context, roleType string, nextToken string) (*zoom.Page[zoom.Role], error)
+	GetRole(ctx context.Context, roleId string) (*zoom.Role, error)
+	CreateRole(ctx context.Context, role *zoom.RoleBody) (*zoom.Role, error)
+	DeleteRole(ctx context.Context, roleId string) error
+	GetRoleMembers(ctx context.Context, roleId string, nextToken string) (*zoom.Page[zoom.User], error)
+	AssignRole(ctx context.Context, roleId, userId string) error
+	UnassignRole(ctx context.Context, roleId, userId string) error
+}
+
+// principalAPI looks up the users protected from destructive operations.
+type principalAPI interface {
+	GetUser(ctx context.Context, userId string) (zoom.User, *http.Response, error)
+}
+
+type licenseAPI interface {
+	dryRunner
+	userLister
+	GetUser(ctx context.Context, userId string) (zoom.User, *http.Response, error)
+	UpdateUser(ctx context.Context, userId string, update *zoom.UserUpdateBody) error
+	GetUserSettings(ctx context.Context, userId string) (*zoom.UserSettings, error)
+	UpdateUserFeatures(ctx context.Context, userId string, features map[string]interface{}) error
+}
+
+type contactGroupAPI interface {
+	GetContactGroups(ctx context.Context, nextToken string) (*zoom.Page[zoom.ContactGroup], error)
+	GetContactGroupMembers(ctx context.Context, groupId string, nextToken string) (*zoom.Page[zoom.GroupMember], error)
+}
+
+// ZoomAPI is everything the connector needs from Zoom, implemented by *zoom.Client.
+type ZoomAPI interface {
+	userAPI
+	groupAPI
+	roleAPI
+	licenseAPI
+	sessionAPI
+	contactGroupAPI
+}
+
+var _ ZoomAPI = (*zoom.Client)(nil)
@@ -3,6 +3,8 @@ package connector
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
@@ -38,14 +40,146 @@ var (
 	resourceTypeRole = &v2.ResourceType{
 		Id:          "role",
 		DisplayName: "Role",
+		Description: "A Zoom user has exactly one common role. Granting a common role replaces the user's current one, revoking one moves the user to the default role. IQ and phone roles are held alongside it.",
 		Traits: []v2.ResourceType_Trait{
 			v2.ResourceType_TRAIT_ROLE,
 		},
 	}
+	resourceTypeLicense = &v2.ResourceType{
+		Id:          "license",
+		DisplayName: "License",
+		Description: "A Zoom user type or add-on plan. A user has exactly one user type, add-ons require a Licensed user.",
+	}
 )
 
 type Zoom struct {
-	client *zoom.Client
+	client           ZoomAPI
+	defaultRole      string
+	forceDeleteRoles bool
+	protected        []string
+	userStatuses     []string
+	deprovision      deprovisioning
+	resendInvites    bool
+	userDetails      bool
+}
+
+type config struct {
+	endpoints        *zoom.Endpoints
+	clientOptions    []zoom.ClientOption
+	recordDir        string
+	replayDir        string
+	defaultRole      string
+	forceDeleteRoles bool
+	protected        []string
+	dryRun           bool
+	userStatuses     []string
+	deprovision      deprovisioning
+	resendInvites    bool
+	userDetails      bool
+}
+
+type Option func(*config)
+
+// WithEndpoints points the connector at another Zoom environment, defaults to the commercial cloud.
+func WithEndpoints(endpoints zoom.Endpoints) Option {
+	return func(c *config) {
+		c.endpoints = &endpoints
+	}
+}
+
+// WithRecordDir saves every sanitized Zoom request/response pair to dir.
+func WithRecordDir(dir string) Option {
+	return func(c *config) {
+		c.recordDir = dir
+	}
+}
+
+// WithReplayDir serves Zoom responses from a directory written with WithRecordDir instead of the network.
+func WithReplayDir(dir string) Option {
+	return func(c *config) {
+		c.replayDir = dir
+	}
+}
+
+// WithDefaultRole sets the role, by name or ID, users are moved to when their role is revoked. Defaults to Member.
+func WithDefaultRole(role string) Option {
+	return func(c *config) {
+		if role != "" {
+			c.defaultRole = role
+		}
+	}
+}
+
+// WithForceDeleteRoles lets custom roles that still have members be deleted, their members are moved out first.
+func WithForceDeleteRoles(force bool) Option {
+	return func(c *config) {
+		c.forceDeleteRoles = force
+	}
+}
+
+// WithProtectedPrincipals adds user IDs or emails that provisioning must never revoke access from or delete.
+// Owners and the account owner are always protected.
+func WithProtectedPrincipals(principals ...string) Option {
+	return func(c *config) {
+		c.protected = append(c.protected, principals...)
+	}
+}
+
+// WithDryRun logs the Zoom requests provisioning would send instead of sending them.
+func WithDryRun(dryRun bool) Option {
+	return func(c *config) {
+		c.dryRun = dryRun
+	}
+}
+
+// WithUserStatuses limits the synced users to the given statuses, see zoom.UserStatuses. Defaults to all of them.
+func WithUserStatuses(statuses ...string) Option {
+	return func(c *config) {
+		if len(statuses) > 0 {
+			c.userStatuses = statuses
+		}
+	}
+}
+
+// WithDeprovisionMode sets how deleted users are deprovisioned, see DeprovisionModes. Defaults to disassociate.
+func WithDeprovisionMode(mode string) Option {
+	return func(c *config) {
+		if mode != "" {
+			c.deprovision.mode = mode
+		}
+	}
+}
+
+// WithTransfer hands the given content types of disassociated or deleted users to email, see TransferContentTypes.
+// Pass TransferToManager as email to transfer to each user's manager.
+func WithTransfer(email string, content ...string) Option {
+	return func(c *config) {
+		c.deprovision.transferEmail = email
+		c.deprovision.transferContent = content
+	}
+}
+
+// WithResendInvites makes account creation send a new invitation to users that already exist but are still pending,
+// instead of just returning them.
+func WithResendInvites(resend bool) Option {
+	return func(c *config) {
+		c.resendInvites = resend
+	}
+}
+
+// WithUserDetails makes the user sync get every user on its own for the job title, manager, location and login types,
+// which Zoom leaves out of user listings. It costs one extra Zoom request per user and sync.
+func WithUserDetails(details bool) Option {
+	return func(c *config) {
+		c.userDetails = details
+	}
+}
+
+// WithClientOptions passes options through to the underlying zoom.Client.
+func WithClientOptions(opts ...zoom.ClientOption) Option {
+	return func(c *config) {
+		c.clientOptions = append(c.clientOptions, opts...)
+	}
 }
 
 func New(
@@ -53,26 +187,81 @@ func New(
 	accountId string,
 	clientId string,
 	clientSecret string,
+	opts ...Option,
 ) (*Zoom, error) {
+	cfg := &config{
+		defaultRole:  defaultRoleName,
+		userStatuses: zoom.UserStatuses,
+		deprovision:  deprovisioning{mode: DeprovisionDisassociate},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	for _, userStatus := range cfg.userStatuses {
+		if !slices.Contains(zoom.UserStatuses, userStatus) {
+			return nil, fmt.Errorf("zoom-connector: unknown user status %q, expected one of %s", userStatus, strings.Join(zoom.UserStatuses, ", "))
+		}
+	}
+
+	if err := cfg.deprovision.validate(); err != nil {
+		return nil, fmt.Errorf("zoom-connector: %w", err)
+	}
+
+	if cfg.dryRun {
+		cfg.clientOptions = append(cfg.clientOptions, zoom.WithDryRun())
+	}
+
+	if cfg.endpoints == nil {
+		endpoints, err := zoom.EndpointsFor(zoom.EnvironmentCommercial)
+		if err != nil {
+			return nil, err
+		}
+		cfg.endpoints = &endpoints
+	}
+
 	httpClient, err := uhttp.NewClient(ctx, uhttp.WithLogger(true, ctxzap.Extract(ctx)))
 	if err != nil {
 		return nil, err
 	}
 
-	token, err := zoom.RequestAccessToken(ctx, accountId, clientId, clientSecret)
-	if err != nil {
+	switch {
+	case cfg.replayDir != "":
+		replay, err := zoom.NewReplayTransport(cfg.replayDir)
+		if err != nil {
+			return nil, fmt.Errorf("zoom-connector: failed to load replay fixtures: %w", err)
+		}
+		httpClient.Transport = replay
+	case cfg.recordDir != "":
+		recorder, err := zoom.NewRecordingTransport(httpClient.Transport, cfg.recordDir)
+		if err != nil {
+			return nil, fmt.Errorf("zoom-connector: failed to set up recording: %w", err)
+		}
+		httpClient.Transport = recorder
+	}
+
+	// Fetch the first token eagerly so bad credentials fail fast.
+	tokenSource := zoom.NewTokenSource(httpClient, cfg.endpoints.OAuthURL, accountId, clientId, clientSecret)
+	if _, err := tokenSource.Token(ctx); err != nil {
 		return nil, fmt.Errorf("zoom-connector: failed to get token: %w", err)
 	}
 
 	return &Zoom{
-		client: zoom.NewClient(httpClient, token),
+		client:           zoom.NewClient(httpClient, cfg.endpoints.APIBaseURL, tokenSource, cfg.clientOptions...),
+		defaultRole:      cfg.defaultRole,
+		forceDeleteRoles: cfg.forceDeleteRoles,
+		protected:        cfg.protected,
+		userStatuses:     cfg.userStatuses,
+		deprovision:      cfg.deprovision,
+		resendInvites:    cfg.resendInvites,
+		userDetails:      cfg.userDetails,
 	}, nil
 }
 
 func (z *Zoom) Metadata(_ context.Context) (*v2.ConnectorMetadata, error) {
 	return &v2.ConnectorMetadata{
 		DisplayName: "Zoom",
-		Description: "Connector syncing users, groups, roles and contact groups from Zoom to Baton.",
+		Description: "Connector syncing users, groups, roles, licenses and contact groups from Zoom to Baton.",
 		AccountCreationSchema: &v2.ConnectorAccountCreationSchema{
 			FieldMap: map[string]*v2.ConnectorAccountCreationSchema_Field{
 				"email": {
@@ -115,6 +304,66 @@ func (z *Zoom) Metadata(_ context.Context) (*v2.ConnectorMetadata, error) {
 					Placeholder: "John Doe",
 					Order:       4,
 				},
+				createActionField: {
+					DisplayName: "Create Action",
+					Required:    false,
+					Description: "How Zoom creates the user: create (sends an activation email, the default), autoCreate, custCreate or ssoCreate.",
+					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+					},
+					Placeholder: "create",
+					Order:       5,
+				},
+				userTypeField: {
+					DisplayName: "User Type",
+					Required:    false,
+					Description: "Zoom user type of the user: basic (the default) or licensed.",
+					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+					},
+					Placeholder: "basic",
+					Order:       6,
+				},
+				departmentField: {
+					DisplayName: "Department",
+					Required:    false,
+					Description: "Department of the user.",
+					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+					},
+					Placeholder: "Engineering",
+					Order:       7,
+				},
+				jobTitleField: {
+					DisplayName: "Job Title",
+					Required:    false,
+					Description: "Job title of the user.",
+					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+					},
+					Placeholder: "Software Engineer",
+					Order:       8,
+				},
+				initialGroupField: {
+					DisplayName: "Initial Group",
+					Required:    false,
+					Description: "Name or ID of a group to add the user to.",
+					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+					},
+					Placeholder: "Engineering",
+					Order:       9,
+				},
+				initialRoleField: {
+					DisplayName: "Initial Role",
+					Required:    false,
+					Description: "Name or ID of the role to assign instead of Member.",
+					Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+						StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+					},
+					Placeholder: "Admin",
+					Order:       10,
+				},
 			},
 		},
 	}, nil
@@ -136,10 +385,13 @@ func (z *Zoom) Validate(ctx context.Context) (annotations.Annotations, error) {
 }
 
 func (z *Zoom) ResourceSyncers(ctx context.Context) []connectorbuilder.ResourceSyncer {
+	protected := newProtectedPrincipals(z.client, z.protected)
+
 	return []connectorbuilder.ResourceSyncer{
-		userBuilder(z.client),
-		groupBuilder(z.client),
-		roleBuilder(z.client),
+		userBuilder(z.client, protected, z.userStatuses, z.deprovision, z.resendInvites, z.userDetails),
+		groupBuilder(z.client, protected),
+		roleBuilder(z.client, protected, z.defaultRole, z.forceDeleteRoles),
+		licenseBuilder(z.client, protected, z.userStatuses),
 		contactGroupBuilder(z.client),
 	}
 }
@@ -0,0 +1,171 @@
+package connector
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-zoom/pkg/zoom"
+	"github.com/conductorone/baton-zoom/pkg/zoom/zoomtest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func newTestConnector(t *testing.T, srv *zoomtest.Server) *Zoom {
+	t.Helper()
+
+	z, err := New(
+		ctx,
+		zoomtest.AccountID,
+		zoomtest.ClientID,
+		zoomtest.ClientSecret,
+		WithEndpoints(srv.Endpoints()),
+		WithClientOptions(zoom.WithPageSize(2)),
+	)
+	require.NoError(t, err)
+
+	return z
+}
+
+func TestSyncGroupsAgainstFakeZoom(t *testing.T) {
+	srv := zoomtest.NewServer()
+	defer srv.Close()
+
+	jane := srv.AddUser(zoom.User{Email: "jane@example.com", DisplayName: "Jane"})
+	john := srv.AddUser(zoom.User{Email: "john@example.com", DisplayName: "John"})
+	srv.AddUser(zoom.User{Email: "ann@example.com", DisplayName: "Ann"})
+	group := srv.AddGroup(zoom.Group{Name: "Engineering"})
+	srv.AddGroupMember(group.ID, jane.ID)
+	srv.AddGroupAdmin(group.ID, john.ID)
+
+	z := newTestConnector(t, srv)
+
+	users := userBuilder(z.client, newProtectedPrincipals(z.client, nil), zoom.UserStatuses, deprovisioning{mode: DeprovisionDisassociate}, false, false)
+	var listed []*v2.Resource
+	token := &pagination.Token{}
+	for {
+		rs, next, _, err := users.List(ctx, nil, token)
+		require.NoError(t, err)
+		listed = append(listed, rs...)
+		if next == "" {
+			break
+		}
+		token = &pagination.Token{Token: next}
+	}
+	require.Len(t, listed, 3)
+
+	groups := groupBuilder(z.client, newProtectedPrincipals(z.client, nil))
+	rs, _, _, err := groups.List(ctx, nil, &pagination.Token{})
+	require.NoError(t, err)
+	require.Len(t, rs, 1)
+
+	grants, _, _, err := groups.Grants(ctx, rs[0], &pagination.Token{})
+	require.NoError(t, err)
+	require.Len(t, grants, 2)
+	require.Equal(t, jane.ID, grants[0].Principal.Id.Resource)
+	require.Equal(t, john.ID, grants[1].Principal.Id.Resource)
+}
+
+func TestCreateAccountAdoptsExistingUsers(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		status         string
+		resendInvites  bool
+		protected      []string
+		extra          map[string]interface{}
+		wantResent     bool
+		wantAction     string
+		wantGroup      bool
+		wantDepartment string
+		wantCode       codes.Code
+	}{
+		{
+			name:           "active",
+			status:         zoom.UserStatusActive,
+			resendInvites:  true,
+			extra:          map[string]interface{}{departmentField: "Engineering", initialGroupField: "Engineering"},
+			wantGroup:      true,
+			wantDepartment: "Engineering",
+		},
+		{name: "pending", status: zoom.UserStatusPending},
+		{name: "inactive", status: zoom.UserStatusInactive, wantAction: "deactivated"},
+		{name: "pending resent", status: zoom.UserStatusPending, resendInvites: true, wantResent: true},
+		{
+			name:          "pending protected",
+			status:        zoom.UserStatusPending,
+			resendInvites: true,
+			protected:     []string{"jane@example.com"},
+			wantCode:      codes.PermissionDenied,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := map[string]interface{}{
+				"email":        "jane@example.com",
+				"first_name":   "Jane",
+				"last_name":    "Doe",
+				"display_name": "Jane Doe",
+			}
+			maps.Copy(fields, tt.extra)
+			profile, err := structpb.NewStruct(fields)
+			require.NoError(t, err)
+
+			srv := zoomtest.NewServer()
+			defer srv.Close()
+
+			srv.AddUser(zoom.User{Email: "owner@example.com", RoleID: zoomtest.OwnerRoleID})
+			jane := srv.AddUser(zoom.User{Email: "jane@example.com", Status: tt.status})
+			group := srv.AddGroup(zoom.Group{Name: "Engineering"})
+
+			z := newTestConnector(t, srv)
+			users := userBuilder(z.client, newProtectedPrincipals(z.client, tt.protected), zoom.UserStatuses, deprovisioning{mode: DeprovisionDisassociate}, tt.resendInvites, false)
+
+			resp, _, annos, err := users.CreateAccount(ctx, &v2.AccountInfo{Profile: profile}, nil)
+			if tt.wantCode != codes.OK {
+				require.Equal(t, tt.wantCode, status.Code(err))
+				_, ok := srv.User(jane.ID)
+				require.True(t, ok)
+				return
+			}
+			require.NoError(t, err)
+
+			if tt.wantResent {
+				// The invitation is re-sent by creating the user again, under a new ID.
+				result, ok := resp.(*v2.CreateAccountResponse_SuccessResult)
+				require.True(t, ok)
+				require.NotEqual(t, jane.ID, result.Resource.Id.Resource)
+				require.Contains(t, srv.Requests(), "DELETE /v2/users/"+jane.ID)
+				_, ok = srv.User(jane.ID)
+				require.False(t, ok)
+				got, ok := srv.User(result.Resource.Id.Resource)
+				require.True(t, ok)
+				require.Equal(t, zoom.UserStatusPending, got.Status)
+				return
+			}
+
+			picked := &structpb.Struct{}
+			ok, err := annos.Pick(picked)
+			require.NoError(t, err)
+			require.True(t, ok && picked.Fields["already_exists"].GetBoolValue())
+			require.NotContains(t, srv.Requests(), "DELETE /v2/users/"+jane.ID)
+
+			if tt.wantAction != "" {
+				result, ok := resp.(*v2.CreateAccountResponse_ActionRequiredResult)
+				require.True(t, ok)
+				require.Equal(t, jane.ID, result.Resource.Id.Resource)
+				require.Contains(t, result.Message, tt.wantAction)
+				return
+			}
+			result, ok := resp.(*v2.CreateAccountResponse_SuccessResult)
+			require.True(t, ok)
+			require.Equal(t, jane.ID, result.Resource.Id.Resource)
+
+			got, _ := srv.User(jane.ID)
+			require.Equal(t, tt.wantDepartment, got.Department)
+			require.Equal(t, tt.wantGroup, slices.Contains(srv.GroupMemberIDs(group.ID), jane.ID))
+		})
+	}
+}
@@ -15,7 +15,7 @@ import (
 
 type contactGroupResourceType struct {
 	resourceType *v2.ResourceType
-	client       *zoom.Client
+	client       contactGroupAPI
 }
 
 func (g *contactGroupResourceType) ResourceType(_ context.Context) *v2.ResourceType {
@@ -56,25 +56,24 @@ func (g *contactGroupResourceType) List(ctx context.Context, parentId *v2.Resour
 		return nil, "", nil, err
 	}
 
-	groups, nextToken, resp, err := g.client.GetContactGroups(ctx, page)
+	groups, err := g.client.GetContactGroups(ctx, page)
 	if err != nil {
 		return nil, "", nil, err
 	}
-	resp.Body.Close()
 
-	if nextToken != "" {
-		pageToken, err = bag.NextToken(nextToken)
+	if groups.NextPageToken != "" {
+		pageToken, err = bag.NextToken(groups.NextPageToken)
 		if err != nil {
 			return nil, "", nil, err
 		}
 	}
 
-	annos, err := parseResp(resp)
+	annos, err := parseResp(groups.Response)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	for _, group := range groups {
+	for _, group := range groups.Items {
 		groupCopy := group
 		cgr, err := contactGroupResource(groupCopy, parentId)
 		if err != nil {
@@ -110,26 +109,24 @@ func (g *contactGroupResourceType) Grants(ctx context.Context, resource *v2.Reso
 		return nil, "", nil, err
 	}
 
-	groupMembers, nextToken, resp, err := g.client.GetContactGroupMembers(ctx, resource.Id.Resource, page)
+	groupMembers, err := g.client.GetContactGroupMembers(ctx, resource.Id.Resource, page)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	resp.Body.Close()
-
-	if nextToken != "" {
-		pageToken, err = bag.NextToken(nextToken)
+	if groupMembers.NextPageToken != "" {
+		pageToken, err = bag.NextToken(groupMembers.NextPageToken)
 		if err != nil {
 			return nil, "", nil, err
 		}
 	}
 
-	annos, err := parseResp(resp)
+	annos, err := parseResp(groupMembers.Response)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	for _, member := range groupMembers {
+	for _, member := range groupMembers.Items {
 		memberCopy := member
 		// member type 1 is user, 2 is user group
 		if member.Type == 1 {
@@ -160,7 +157,7 @@ func (g *contactGroupResourceType) Grants(ctx context.Context, resource *v2.Reso
 	return rv, pageToken, annos, nil
 }
 
-func contactGroupBuilder(client *zoom.Client) *contactGroupResourceType {
+func contactGroupBuilder(client contactGroupAPI) *contactGroupResourceType {
 	return &contactGroupResourceType{
 		resourceType: resourceTypeContactGroup,
 		client:       client,
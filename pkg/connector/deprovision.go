@@ -0,0 +1,134 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/conductorone/baton-zoom/pkg/zoom"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Ways a deleted user is deprovisioned, see WithDeprovisionMode.
+const (
+	// DeprovisionDeactivate keeps the user and their content but blocks sign in.
+	DeprovisionDeactivate = "deactivate"
+	// DeprovisionDisassociate removes the user from the account, they keep a Basic account of their own.
+	DeprovisionDisassociate = "disassociate"
+	// DeprovisionDelete permanently deletes the user and any content not transferred.
+	DeprovisionDelete = "delete"
+)
+
+var DeprovisionModes = []string{DeprovisionDeactivate, DeprovisionDisassociate, DeprovisionDelete}
+
+// Content that can be transferred when a user is disassociated or deleted, see WithTransfer.
+const (
+	TransferMeetings    = "meetings"
+	TransferWebinars    = "webinars"
+	TransferRecordings  = "recordings"
+	TransferWhiteboards = "whiteboards"
+)
+
+var TransferContentTypes = []string{TransferMeetings, TransferWebinars, TransferRecordings, TransferWhiteboards}
+
+// TransferToManager as the transfer email hands content to the manager on the user's profile.
+const TransferToManager = "manager"
+
+type deprovisioning struct {
+	mode            string
+	transferEmail   string
+	transferContent []string
+}
+
+func (d deprovisioning) validate() error {
+	if !slices.Contains(DeprovisionModes, d.mode) {
+		return fmt.Errorf("unknown deprovision mode %q", d.mode)
+	}
+	for _, content := range d.transferContent {
+		if !slices.Contains(TransferContentTypes, content) {
+			return fmt.Errorf("unknown transfer content %q", content)
+		}
+	}
+	if len(d.transferContent) > 0 && d.transferEmail == "" {
+		return fmt.Errorf("transfer content requires a transfer email")
+	}
+	if d.transferEmail != "" && d.mode == DeprovisionDeactivate {
+		return fmt.Errorf("content can't be transferred when users are only deactivated")
+	}
+
+	return nil
+}
+
+// deleteOptions are the DELETE /users/{userId} options for user, resolving the manager as transfer target.
+func (d deprovisioning) deleteOptions(user zoom.User) (zoom.DeleteUserOptions, error) {
+	opts := zoom.DeleteUserOptions{Action: zoom.UserDeleteAction(d.mode)}
+	if d.transferEmail == "" || len(d.transferContent) == 0 {
+		return opts, nil
+	}
+
+	opts.TransferEmail = d.transferEmail
+	if d.transferEmail == TransferToManager {
+		if user.Manager == "" {
+			return opts, status.Errorf(codes.FailedPrecondition, "baton-zoom: user %s has no manager to transfer their content to", user.ID)
+		}
+		opts.TransferEmail = user.Manager
+	}
+	opts.TransferMeetings = slices.Contains(d.transferContent, TransferMeetings)
+	opts.TransferWebinars = slices.Contains(d.transferContent, TransferWebinars)
+	opts.TransferRecordings = slices.Contains(d.transferContent, TransferRecordings)
+	opts.TransferWhiteboards = slices.Contains(d.transferContent, TransferWhiteboards)
+
+	return opts, nil
+}
+
+// deactivate signs the user out for good without removing them, a no-op for inactive users.
+func (u *userResourceType) deactivate(ctx context.Context, user zoom.User) (bool, error) {
+	if user.Status == zoom.UserStatusInactive {
+		return false, nil
+	}
+
+	err := u.client.UpdateUserStatus(ctx, user.ID, zoom.DeactivateUser)
+	if err != nil {
+		return false, fmt.Errorf("baton-zoom: failed to deactivate user %s: %w", user.ID, err)
+	}
+	if u.client.DryRun() {
+		return true, nil
+	}
+
+	updated, _, err := u.client.GetUser(ctx, user.ID)
+	if err != nil {
+		return false, fmt.Errorf("baton-zoom: failed to confirm user %s was deactivated: %w", user.ID, err)
+	}
+	if updated.Status != zoom.UserStatusInactive {
+		return false, fmt.Errorf("baton-zoom: user %s is %s after the deactivation", user.ID, updated.Status)
+	}
+
+	return true, nil
+}
+
+// remove disassociates or deletes the user, transferring their content first if configured.
+func (u *userResourceType) remove(ctx context.Context, user zoom.User) error {
+	opts, err := u.deprovision.deleteOptions(user)
+	if err != nil {
+		return err
+	}
+
+	err = u.client.DeleteUser(ctx, user.ID, opts)
+	if err != nil {
+		return fmt.Errorf("baton-zoom: failed to %s user %s: %w", u.deprovision.mode, user.ID, err)
+	}
+	if u.client.DryRun() {
+		return nil
+	}
+
+	_, _, err = u.client.GetUser(ctx, user.ID)
+	if err == nil {
+		return fmt.Errorf("baton-zoom: user %s still exists after the %s", user.ID, u.deprovision.mode)
+	}
+	if status.Code(err) != codes.NotFound {
+		return fmt.Errorf("baton-zoom: failed to confirm user %s was removed: %w", user.ID, err)
+	}
+
+	return nil
+}
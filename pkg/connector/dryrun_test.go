@@ -0,0 +1,126 @@
+package connector
+
+import (
+	"strings"
+	"testing"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-zoom/pkg/zoom"
+	"github.com/conductorone/baton-zoom/pkg/zoom/zoomtest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func isDryRun(t *testing.T, annos annotations.Annotations) bool {
+	t.Helper()
+
+	s := &structpb.Struct{}
+	ok, err := annos.Pick(s)
+	require.NoError(t, err)
+
+	return ok && s.Fields["dry_run"].GetBoolValue()
+}
+
+func TestDryRunChangesNothing(t *testing.T) {
+	srv := zoomtest.NewServer()
+	defer srv.Close()
+
+	srv.AddUser(zoom.User{Email: "owner@example.com", RoleID: zoomtest.OwnerRoleID})
+	jane := srv.AddUser(zoom.User{Email: "jane@example.com", RoleID: zoomtest.MemberRoleID})
+	john := srv.AddUser(zoom.User{Email: "john@example.com", RoleID: zoomtest.AdminRoleID})
+	srv.AddUser(zoom.User{Email: "joe@example.com", RoleID: zoomtest.AdminRoleID})
+	group := srv.AddGroup(zoom.Group{Name: "Engineering"})
+	srv.AddGroupMember(group.ID, john.ID)
+
+	client := srv.NewClient(zoom.WithDryRun())
+	protected := newProtectedPrincipals(client, nil)
+	users := userBuilder(client, protected, zoom.UserStatuses, deprovisioning{mode: DeprovisionDisassociate}, false, false)
+	groups := groupBuilder(client, protected)
+	roles := roleBuilder(client, protected, defaultRoleName, false)
+
+	groupResource := testGroup(t, group.ID)
+	adminRole := testRole(t, zoomtest.AdminRoleID)
+
+	_, annos, err := groups.Grant(ctx, testUser(t, jane.ID), ent.NewPermissionEntitlement(groupResource, memberEntitlement))
+	require.NoError(t, err)
+	require.True(t, isDryRun(t, annos))
+
+	_, _, err = groups.Grant(ctx, testUser(t, "missing"), ent.NewPermissionEntitlement(groupResource, memberEntitlement))
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	annos, err = groups.Revoke(ctx, &v2.Grant{
+		Entitlement: ent.NewPermissionEntitlement(groupResource, memberEntitlement),
+		Principal:   testUser(t, john.ID),
+	})
+	require.NoError(t, err)
+	require.True(t, isDryRun(t, annos))
+
+	_, annos, err = roles.Grant(ctx, testUser(t, jane.ID), ent.NewPermissionEntitlement(adminRole, memberEntitlement))
+	require.NoError(t, err)
+	require.True(t, isDryRun(t, annos))
+
+	annos, err = roles.Revoke(ctx, &v2.Grant{
+		Entitlement: ent.NewPermissionEntitlement(adminRole, memberEntitlement),
+		Principal:   testUser(t, john.ID),
+	})
+	require.NoError(t, err)
+	require.True(t, isDryRun(t, annos))
+
+	p, err := structpb.NewStruct(map[string]interface{}{
+		"email":        "new@example.com",
+		"first_name":   "New",
+		"last_name":    "User",
+		"display_name": "New User",
+	})
+	require.NoError(t, err)
+	resp, _, annos, err := users.CreateAccount(ctx, &v2.AccountInfo{Profile: p}, nil)
+	require.NoError(t, err)
+	require.True(t, isDryRun(t, annos))
+	require.IsType(t, &v2.CreateAccountResponse_ActionRequiredResult{}, resp)
+
+	annos, err = users.Delete(ctx, &v2.ResourceId{ResourceType: resourceTypeUser.Id, Resource: jane.ID})
+	require.NoError(t, err)
+	require.True(t, isDryRun(t, annos))
+
+	statuses := &userStatusActions{client: client, protected: protected}
+	rv, annos, err := statuses.disable(ctx, userIdArgs(t, jane.ID))
+	require.NoError(t, err)
+	require.True(t, isDryRun(t, annos))
+	require.Equal(t, zoom.UserStatusActive, rv.Fields[statusResult].GetStringValue())
+
+	rv, annos, err = (&signOutAction{client: client}).signOut(ctx, userIdArgs(t, jane.ID))
+	require.NoError(t, err)
+	require.True(t, isDryRun(t, annos))
+	require.Empty(t, rv.Fields[revokedResult].GetListValue().GetValues())
+
+	for _, r := range srv.Requests() {
+		require.True(t, strings.HasPrefix(r, "GET "), r)
+	}
+	require.Equal(t, []string{john.ID}, srv.GroupMemberIDs(group.ID))
+	got, ok := srv.User(jane.ID)
+	require.True(t, ok)
+	require.Equal(t, zoomtest.MemberRoleID, got.RoleID)
+	require.Equal(t, zoom.UserStatusActive, got.Status)
+	got, ok = srv.User(john.ID)
+	require.True(t, ok)
+	require.Equal(t, zoomtest.AdminRoleID, got.RoleID)
+}
+
+func TestMarkersKeepEachOther(t *testing.T) {
+	annos := withMarkers(nil, alreadyExistsMarker)
+	annos.Update(&v2.GrantAlreadyExists{})
+	annos = withMarkers(annos, dryRunMarker)
+
+	require.True(t, isDryRun(t, annos))
+	require.True(t, annos.Contains(&v2.GrantAlreadyExists{}))
+
+	s := &structpb.Struct{}
+	_, err := annos.Pick(s)
+	require.NoError(t, err)
+	require.True(t, s.Fields[alreadyExistsMarker].GetBoolValue())
+	require.Len(t, annos, 2)
+}
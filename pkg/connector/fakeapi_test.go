@@ -0,0 +1,484 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/conductorone/baton-zoom/pkg/zoom"
+)
+
+const (
+	fakeMemberRoleID = "role-member"
+	// fakeOwnerID is the account owner returned for "me" unless the test adds its own.
+	fakeOwnerID = "owner"
+)
+
+var fakeRoles = []zoom.Role{
+	{ID: fakeMemberRoleID, Name: "Member"},
+	{ID: "role-0", Name: "Admin"},
+	{ID: "role-1", Name: "Auditor"},
+	{ID: "role-basic", Name: "Basic"},
+}
+
+// fakeAPI is an in-memory ZoomAPI for unit tests. Calls that change state are
+// recorded in calls, and any method can be made to fail through errs, keyed by
+// method name. Methods not implemented here panic through the nil embedded interface.
+type fakeAPI struct {
+	ZoomAPI
+
+	users        map[string]zoom.User
+	groups       []zoom.Group
+	groupMembers map[string][]zoom.User
+	groupAdmins  map[string][]zoom.User
+	roleMembers  map[string][]zoom.User
+	roles        []zoom.Role
+	features     map[string]zoom.UserFeatures
+	// pageSize is the number of items per page of paginated methods, everything fits on one page if zero.
+	pageSize int
+	// keepDeletedUsers makes DeleteUser and UpdateUserStatus succeed without changing the user.
+	keepDeletedUsers bool
+	// dryRun makes DryRun report a client that only logs changes.
+	dryRun bool
+
+	errs  map[string]error
+	calls []string
+	// reads records the calls of read methods that tests count.
+	reads []string
+}
+
+func (f *fakeAPI) DryRun() bool {
+	return f.dryRun
+}
+
+func (f *fakeAPI) call(method string, args ...string) error {
+	if err := f.errs[method]; err != nil {
+		return err
+	}
+
+	call := method
+	for _, a := range args {
+		call += " " + a
+	}
+	f.calls = append(f.calls, call)
+
+	return nil
+}
+
+func fakePage[T any](items []T, token string, pageSize int) *zoom.Page[T] {
+	start := 0
+	if token != "" {
+		start, _ = strconv.Atoi(token)
+	}
+	if pageSize == 0 {
+		pageSize = len(items)
+	}
+
+	end := min(start+pageSize, len(items))
+	page := &zoom.Page[T]{Items: items[start:end], TotalRecords: len(items)}
+	if end < len(items) {
+		page.NextPageToken = strconv.Itoa(end)
+	}
+
+	return page
+}
+
+func userNotFound(userId string) error {
+	return &zoom.APIError{StatusCode: http.StatusNotFound, Code: 1001, Message: fmt.Sprintf("User does not exist: %s.", userId)}
+}
+
+func (f *fakeAPI) GetUsers(_ context.Context, status string, nextToken string) (*zoom.Page[zoom.User], error) {
+	if err := f.errs["GetUsers "+status]; err != nil {
+		return nil, err
+	}
+	f.reads = append(f.reads, "GetUsers "+status)
+
+	var users []zoom.User
+	for _, u := range f.users {
+		if u.Status == status {
+			users = append(users, u)
+		}
+	}
+	slices.SortFunc(users, func(a, b zoom.User) int { return strings.Compare(a.ID, b.ID) })
+
+	return fakePage(users, nextToken, f.pageSize), nil
+}
+
+func (f *fakeAPI) GetUser(_ context.Context, userId string) (zoom.User, *http.Response, error) {
+	if err := f.errs["GetUser"]; err != nil {
+		return zoom.User{}, nil, err
+	}
+	f.reads = append(f.reads, "GetUser "+userId)
+
+	u, ok := f.users[userId]
+	if !ok && userId == accountOwnerId {
+		return zoom.User{ID: fakeOwnerID, RoleID: zoom.OwnerRoleID}, nil, nil
+	}
+	if !ok {
+		return zoom.User{}, nil, userNotFound(userId)
+	}
+	u.GroupIDs = nil
+	for groupId, members := range f.groupMembers {
+		if containsUser(members, u.ID) {
+			u.GroupIDs = append(u.GroupIDs, groupId)
+		}
+	}
+
+	return u, nil, nil
+}
+
+func (f *fakeAPI) CreateUser(_ context.Context, newUser *zoom.UserCreationBody) (*zoom.UserCreationResponse, error) {
+	if err := f.call("CreateUser", string(newUser.Action), newUser.UserInfo.Email); err != nil {
+		return nil, err
+	}
+
+	if f.users == nil {
+		f.users = map[string]zoom.User{}
+	}
+	f.users["new-user"] = zoom.User{
+		ID:        "new-user",
+		Email:     newUser.UserInfo.Email,
+		FirstName: newUser.UserInfo.FirstName,
+		LastName:  newUser.UserInfo.LastName,
+		Type:      int(newUser.UserInfo.Type),
+		RoleID:    fakeMemberRoleID,
+	}
+
+	return &zoom.UserCreationResponse{
+		Id:        "new-user",
+		Email:     newUser.UserInfo.Email,
+		FirstName: newUser.UserInfo.FirstName,
+		LastName:  newUser.UserInfo.LastName,
+		Type:      int(newUser.UserInfo.Type),
+	}, nil
+}
+
+func (f *fakeAPI) DeleteUser(_ context.Context, userId string, opts zoom.DeleteUserOptions) error {
+	args := []string{userId}
+	if opts.Action != "" {
+		args = append(args, string(opts.Action))
+	}
+	if opts.TransferEmail != "" {
+		args = append(args, "to="+opts.TransferEmail)
+		for _, transfer := range []struct {
+			content string
+			ok      bool
+		}{
+			{TransferMeetings, opts.TransferMeetings},
+			{TransferWebinars, opts.TransferWebinars},
+			{TransferRecordings, opts.TransferRecordings},
+			{TransferWhiteboards, opts.TransferWhiteboards},
+		} {
+			if transfer.ok {
+				args = append(args, transfer.content)
+			}
+		}
+	}
+	if err := f.call("DeleteUser", args...); err != nil {
+		return err
+	}
+	if _, ok := f.users[userId]; !ok {
+		return userNotFound(userId)
+	}
+	if !f.keepDeletedUsers {
+		delete(f.users, userId)
+	}
+
+	return nil
+}
+
+func (f *fakeAPI) UpdateUserStatus(_ context.Context, userId string, action string) error {
+	if err := f.call("UpdateUserStatus", userId, action); err != nil {
+		return err
+	}
+	user, ok := f.users[userId]
+	if !ok {
+		return userNotFound(userId)
+	}
+	if !f.keepDeletedUsers {
+		user.Status = map[string]string{zoom.ActivateUser: zoom.UserStatusActive, zoom.DeactivateUser: zoom.UserStatusInactive}[action]
+		f.users[userId] = user
+	}
+
+	return nil
+}
+
+func (f *fakeAPI) RevokeSSOToken(_ context.Context, userId string) error {
+	if err := f.call("RevokeSSOToken", userId); err != nil {
+		return err
+	}
+	if _, ok := f.users[userId]; !ok {
+		return userNotFound(userId)
+	}
+
+	return nil
+}
+
+func (f *fakeAPI) GetGroups(_ context.Context, nextToken string) (*zoom.Page[zoom.Group], error) {
+	if err := f.errs["GetGroups"]; err != nil {
+		return nil, err
+	}
+
+	return fakePage(f.groups, nextToken, f.pageSize), nil
+}
+
+func (f *fakeAPI) GetGroupMembers(_ context.Context, groupId string) ([]zoom.User, error) {
+	if err := f.errs["GetGroupMembers"]; err != nil {
+		return nil, err
+	}
+
+	return f.groupMembers[groupId], nil
+}
+
+func (f *fakeAPI) GetGroupAdmins(_ context.Context, groupId string) ([]zoom.User, error) {
+	if err := f.errs["GetGroupAdmins"]; err != nil {
+		return nil, err
+	}
+
+	return f.groupAdmins[groupId], nil
+}
+
+func addMember(members map[string][]zoom.User, key string, userId string) map[string][]zoom.User {
+	if members == nil {
+		members = map[string][]zoom.User{}
+	}
+	members[key] = append(members[key], zoom.User{ID: userId})
+
+	return members
+}
+
+func removeMember(members map[string][]zoom.User, key string, userId string) {
+	if ids, ok := members[key]; ok {
+		members[key] = slices.DeleteFunc(ids, func(u zoom.User) bool { return u.ID == userId })
+	}
+}
+
+func (f *fakeAPI) AddGroupMembers(_ context.Context, groupId, userId string) error {
+	if err := f.call("AddGroupMembers", groupId, userId); err != nil {
+		return err
+	}
+	f.groupMembers = addMember(f.groupMembers, groupId, userId)
+
+	return nil
+}
+
+func (f *fakeAPI) AddGroupAdmins(_ context.Context, groupId, userId string) error {
+	if err := f.call("AddGroupAdmins", groupId, userId); err != nil {
+		return err
+	}
+	f.groupAdmins = addMember(f.groupAdmins, groupId, userId)
+
+	return nil
+}
+
+func (f *fakeAPI) DeleteGroupMember(_ context.Context, groupId, userId string) error {
+	if err := f.call("DeleteGroupMember", groupId, userId); err != nil {
+		return err
+	}
+	removeMember(f.groupMembers, groupId, userId)
+
+	return nil
+}
+
+func (f *fakeAPI) DeleteGroupAdmin(_ context.Context, groupId, userId string) error {
+	if err := f.call("DeleteGroupAdmin", groupId, userId); err != nil {
+		return err
+	}
+	removeMember(f.groupAdmins, groupId, userId)
+
+	return nil
+}
+
+func (f *fakeAPI) GetRoleMembers(_ context.Context, roleId string, nextToken string) (*zoom.Page[zoom.User], error) {
+	if err := f.errs["GetRoleMembers"]; err != nil {
+		return nil, err
+	}
+
+	return fakePage(f.roleMembers[roleId], nextToken, f.pageSize), nil
+}
+
+func (f *fakeAPI) isCommonRole(roleId string) bool {
+	for _, role := range f.roles {
+		if role.ID == roleId {
+			return role.Type == "" || role.Type == zoom.RoleTypeCommon
+		}
+	}
+
+	return true
+}
+
+// AssignRole moves the user from their current common role to roleId, like Zoom does.
+func (f *fakeAPI) AssignRole(_ context.Context, roleId, userId string) error {
+	if err := f.call("AssignRole", roleId, userId); err != nil {
+		return err
+	}
+
+	if !f.isCommonRole(roleId) {
+		f.roleMembers = addMember(f.roleMembers, roleId, userId)
+		return nil
+	}
+
+	u := f.users[userId]
+	removeMember(f.roleMembers, u.RoleID, userId)
+	f.roleMembers = addMember(f.roleMembers, roleId, userId)
+	u.RoleID = roleId
+	f.users[userId] = u
+
+	return nil
+}
+
+// UnassignRole moves the user out of a common role to the Member role, like Zoom does.
+func (f *fakeAPI) UnassignRole(_ context.Context, roleId, userId string) error {
+	if err := f.call("UnassignRole", roleId, userId); err != nil {
+		return err
+	}
+
+	if !f.isCommonRole(roleId) {
+		removeMember(f.roleMembers, roleId, userId)
+		return nil
+	}
+
+	u := f.users[userId]
+	removeMember(f.roleMembers, roleId, userId)
+	f.roleMembers = addMember(f.roleMembers, fakeMemberRoleID, userId)
+	u.RoleID = fakeMemberRoleID
+	f.users[userId] = u
+
+	return nil
+}
+
+func (f *fakeAPI) GetRole(_ context.Context, roleId string) (*zoom.Role, error) {
+	if err := f.errs["GetRole"]; err != nil {
+		return nil, err
+	}
+
+	for _, role := range f.roles {
+		if role.ID == roleId {
+			return &role, nil
+		}
+	}
+
+	return nil, &zoom.APIError{StatusCode: http.StatusNotFound, Code: 1001, Message: fmt.Sprintf("Role does not exist: %s.", roleId)}
+}
+
+func (f *fakeAPI) GetRoles(_ context.Context, roleType string, nextToken string) (*zoom.Page[zoom.Role], error) {
+	if err := f.errs["GetRoles "+roleType]; err != nil {
+		return nil, err
+	}
+
+	var roles []zoom.Role
+	for _, role := range f.roles {
+		if role.Type == roleType || (role.Type == "" && roleType == zoom.RoleTypeCommon) {
+			roles = append(roles, role)
+		}
+	}
+
+	return fakePage(roles, nextToken, f.pageSize), nil
+}
+
+func (f *fakeAPI) CreateRole(_ context.Context, role *zoom.RoleBody) (*zoom.Role, error) {
+	if err := f.call("CreateRole", role.Name); err != nil {
+		return nil, err
+	}
+
+	created := zoom.Role{
+		ID:          fmt.Sprintf("role-%d", len(f.roles)),
+		Name:        role.Name,
+		Description: role.Description,
+		Type:        role.Type,
+		Privileges:  role.Privileges,
+	}
+	f.roles = append(slices.Clone(f.roles), created)
+
+	return &created, nil
+}
+
+func (f *fakeAPI) DeleteRole(_ context.Context, roleId string) error {
+	if err := f.call("DeleteRole", roleId); err != nil {
+		return err
+	}
+
+	f.roles = slices.DeleteFunc(slices.Clone(f.roles), func(role zoom.Role) bool { return role.ID == roleId })
+
+	return nil
+}
+
+func (f *fakeAPI) UpdateUser(_ context.Context, userId string, update *zoom.UserUpdateBody) error {
+	args := []string{userId}
+	if update.Type != 0 {
+		args = append(args, strconv.Itoa(int(update.Type)))
+	}
+	if update.Dept != "" {
+		args = append(args, "dept="+update.Dept)
+	}
+	if update.JobTitle != "" {
+		args = append(args, "job_title="+update.JobTitle)
+	}
+	if err := f.call("UpdateUser", args...); err != nil {
+		return err
+	}
+
+	u, ok := f.users[userId]
+	if !ok {
+		return userNotFound(userId)
+	}
+	if update.Type != 0 {
+		u.Type = int(update.Type)
+	}
+	if update.Dept != "" {
+		u.Department = update.Dept
+	}
+	if update.JobTitle != "" {
+		u.JobTitle = update.JobTitle
+	}
+	f.users[userId] = u
+
+	return nil
+}
+
+func (f *fakeAPI) GetUserSettings(_ context.Context, userId string) (*zoom.UserSettings, error) {
+	if err := f.errs["GetUserSettings"]; err != nil {
+		return nil, err
+	}
+	f.reads = append(f.reads, "GetUserSettings "+userId)
+	if _, ok := f.users[userId]; !ok {
+		return nil, userNotFound(userId)
+	}
+
+	return &zoom.UserSettings{Feature: f.features[userId]}, nil
+}
+
+// UpdateUserFeatures merges the changed flags into the user's features by round-tripping through JSON.
+func (f *fakeAPI) UpdateUserFeatures(_ context.Context, userId string, features map[string]interface{}) error {
+	keys := make([]string, 0, len(features))
+	for k, v := range features {
+		keys = append(keys, fmt.Sprintf("%s=%v", k, v))
+	}
+	slices.Sort(keys)
+	if err := f.call("UpdateUserFeatures", append([]string{userId}, keys...)...); err != nil {
+		return err
+	}
+
+	merged := map[string]interface{}{}
+	b, _ := json.Marshal(f.features[userId])
+	_ = json.Unmarshal(b, &merged)
+	for k, v := range features {
+		merged[k] = v
+	}
+
+	var updated zoom.UserFeatures
+	b, _ = json.Marshal(merged)
+	if err := json.Unmarshal(b, &updated); err != nil {
+		return err
+	}
+	if f.features == nil {
+		f.features = map[string]zoom.UserFeatures{}
+	}
+	f.features[userId] = updated
+
+	return nil
+}
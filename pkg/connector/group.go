@@ -3,6 +3,7 @@ package connector
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
@@ -22,7 +23,8 @@ var entitlements = []string{
 
 type groupResourceType struct {
 	resourceType *v2.ResourceType
-	client       *zoom.Client
+	client       groupAPI
+	protected    *protectedPrincipals
 }
 
 func (g *groupResourceType) ResourceType(_ context.Context) *v2.ResourceType {
@@ -63,25 +65,24 @@ func (g *groupResourceType) List(ctx context.Context, parentId *v2.ResourceId, t
 		return nil, "", nil, err
 	}
 
-	groups, nextToken, resp, err := g.client.GetGroups(ctx, page)
+	groups, err := g.client.GetGroups(ctx, page)
 	if err != nil {
 		return nil, "", nil, err
 	}
-	resp.Body.Close()
 
-	if nextToken != "" {
-		pageToken, err = bag.NextToken(nextToken)
+	if groups.NextPageToken != "" {
+		pageToken, err = bag.NextToken(groups.NextPageToken)
 		if err != nil {
 			return nil, "", nil, err
 		}
 	}
 
-	annos, err := parseResp(resp)
+	annos, err := parseResp(groups.Response)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	for _, group := range groups {
+	for _, group := range groups.Items {
 		groupCopy := group
 		gr, err := groupResource(groupCopy, parentId)
 		if err != nil {
@@ -146,7 +147,7 @@ func (g *groupResourceType) Grants(ctx context.Context, resource *v2.Resource, t
 	return rv, "", nil, nil
 }
 
-func (g *groupResourceType) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+func (g *groupResourceType) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) ([]*v2.Grant, annotations.Annotations, error) {
 	l := ctxzap.Extract(ctx)
 
 	if principal.Id.ResourceType != resourceTypeUser.Id {
@@ -155,23 +156,72 @@ func (g *groupResourceType) Grant(ctx context.Context, principal *v2.Resource, e
 			zap.String("principal_type", principal.Id.ResourceType),
 			zap.String("principal_id", principal.Id.Resource),
 		)
-		return nil, fmt.Errorf("baton-zoom: only users can be granted group membership")
+		return nil, nil, fmt.Errorf("baton-zoom: only users can be granted group membership")
 	}
 
+	groupId := entitlement.Resource.Id.Resource
+	userId := principal.Id.Resource
+
+	var annos annotations.Annotations
+	var err error
 	if entitlement.Slug == memberEntitlement {
-		err := g.client.AddGroupMembers(ctx, entitlement.Resource.Id.Resource, principal.Id.Resource)
+		err = g.client.AddGroupMembers(ctx, groupId, userId)
+	} else {
+		err = g.client.AddGroupAdmins(ctx, groupId, userId)
+	}
+	if err != nil {
+		if !zoom.IsAlreadyMember(err) {
+			return nil, nil, fmt.Errorf("baton-zoom: failed to add %s to group: %w", entitlement.Slug, err)
+		}
+		l.Info(
+			"baton-zoom: user is already in the group",
+			zap.String("group_id", groupId),
+			zap.String("user_id", userId),
+			zap.String("entitlement", entitlement.Slug),
+		)
+		annos.Update(&v2.GrantAlreadyExists{})
+	}
+
+	if g.client.DryRun() {
+		// Nothing was sent to Zoom, so at least make sure the principal is a real user.
+		_, _, err = g.client.GetUser(ctx, userId)
 		if err != nil {
-			return nil, fmt.Errorf("baton-zoom: failed to add user to group: %w", err)
+			return nil, nil, fmt.Errorf("baton-zoom: failed to get user %s: %w", userId, err)
 		}
-		return nil, nil
+		annos = withMarkers(annos, dryRunMarker)
+		return []*v2.Grant{grant.NewGrant(entitlement.Resource, entitlement.Slug, principal.Id)}, annos, nil
+	}
+
+	// Zoom answers before the membership shows up everywhere, so only report the grant once it's listed. Members are
+	// read from the user's group IDs rather than the whole group, admins are few enough to list.
+	var found bool
+	if entitlement.Slug == memberEntitlement {
+		var user zoom.User
+		user, _, err = g.client.GetUser(ctx, userId)
+		found = slices.Contains(user.GroupIDs, groupId)
 	} else {
-		err := g.client.AddGroupAdmins(ctx, entitlement.Resource.Id.Resource, principal.Id.Resource)
-		if err != nil {
-			return nil, fmt.Errorf("baton-zoom: failed to add admin to group: %w", err)
+		var admins []zoom.User
+		admins, err = g.client.GetGroupAdmins(ctx, groupId)
+		found = containsUser(admins, userId)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-zoom: failed to confirm group %s: %w", entitlement.Slug, err)
+	}
+	if !found {
+		return nil, nil, fmt.Errorf("baton-zoom: user %s is not a group %s of %s after the grant", userId, entitlement.Slug, groupId)
+	}
+
+	return []*v2.Grant{grant.NewGrant(entitlement.Resource, entitlement.Slug, principal.Id)}, annos, nil
+}
+
+func containsUser(users []zoom.User, userId string) bool {
+	for _, u := range users {
+		if u.ID == userId {
+			return true
 		}
 	}
 
-	return nil, nil
+	return false
 }
 
 func (g *groupResourceType) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
@@ -189,24 +239,40 @@ func (g *groupResourceType) Revoke(ctx context.Context, grant *v2.Grant) (annota
 		return nil, fmt.Errorf("baton-zoom: only users can have group membership revoked")
 	}
 
+	err := g.protected.check(ctx, principal.Id.Resource, fmt.Sprintf("revoke group %s", entitlement.Slug))
+	if err != nil {
+		return nil, err
+	}
+
 	if entitlement.Slug == memberEntitlement {
-		err := g.client.DeleteGroupMember(ctx, entitlement.Resource.Id.Resource, principal.Id.Resource)
-		if err != nil {
-			return nil, fmt.Errorf("baton-zoom: failed to remove group member: %w", err)
-		}
+		err = g.client.DeleteGroupMember(ctx, entitlement.Resource.Id.Resource, principal.Id.Resource)
 	} else {
-		err := g.client.DeleteGroupAdmin(ctx, entitlement.Resource.Id.Resource, principal.Id.Resource)
-		if err != nil {
-			return nil, fmt.Errorf("baton-zoom: failed to remove group admin: %w", err)
+		err = g.client.DeleteGroupAdmin(ctx, entitlement.Resource.Id.Resource, principal.Id.Resource)
+	}
+	if err != nil {
+		if zoom.IsNotMember(err) || zoom.IsUserNotFound(err) {
+			l.Info(
+				"baton-zoom: user is already out of the group",
+				zap.String("group_id", entitlement.Resource.Id.Resource),
+				zap.String("user_id", principal.Id.Resource),
+				zap.String("entitlement", entitlement.Slug),
+			)
+			return annotations.New(&v2.GrantAlreadyRevoked{}), nil
 		}
+		return nil, fmt.Errorf("baton-zoom: failed to remove group %s: %w", entitlement.Slug, err)
+	}
+
+	if g.client.DryRun() {
+		return withMarkers(nil, dryRunMarker), nil
 	}
 
 	return nil, nil
 }
 
-func groupBuilder(client *zoom.Client) *groupResourceType {
+func groupBuilder(client groupAPI, protected *protectedPrincipals) *groupResourceType {
 	return &groupResourceType{
 		resourceType: resourceTypeGroup,
 		client:       client,
+		protected:    protected,
 	}
 }
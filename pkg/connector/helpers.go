@@ -1,6 +1,7 @@
 package connector
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -8,6 +9,8 @@ import (
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
 	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-zoom/pkg/zoom"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -87,3 +90,83 @@ func annotationsForUserResourceType() annotations.Annotations {
 	annos.Update(&v2.SkipEntitlementsAndGrants{})
 	return annos
 }
+
+// Markers the connector adds to annotations. Annotations hold one message per type, so the markers share a
+// single structpb.Struct keyed by marker and must be set through withMarkers to not overwrite each other.
+const (
+	// exclusiveGrantsMarker marks the member entitlement of a common role, a user holds exactly one of them.
+	exclusiveGrantsMarker = "exclusive_grants"
+	// dryRunMarker marks the result of a provisioning operation that was only logged, not sent to Zoom.
+	dryRunMarker = "dry_run"
+	// alreadyExistsMarker marks an account creation that found the user already in Zoom and adopted them.
+	alreadyExistsMarker = "already_exists"
+)
+
+// markerAnnotation is the annotation carrying the given markers.
+func markerAnnotation(markers ...string) *structpb.Struct {
+	rv := &structpb.Struct{Fields: make(map[string]*structpb.Value, len(markers))}
+	for _, marker := range markers {
+		rv.Fields[marker] = structpb.NewBoolValue(true)
+	}
+
+	return rv
+}
+
+// withMarkers adds markers to annos, keeping the markers already set.
+func withMarkers(annos annotations.Annotations, markers ...string) annotations.Annotations {
+	rv := markerAnnotation(markers...)
+
+	existing := &structpb.Struct{}
+	ok, err := annos.Pick(existing)
+	if err == nil && ok {
+		for marker, value := range existing.GetFields() {
+			if _, set := rv.Fields[marker]; !set {
+				rv.Fields[marker] = value
+			}
+		}
+	}
+
+	annos.Update(rv)
+	return annos
+}
+
+// findPaged walks a paginated Zoom list and returns the first item matching match, ok is false when there is none.
+func findPaged[T any](ctx context.Context, list func(ctx context.Context, nextToken string) (*zoom.Page[T], error), match func(T) bool) (T, bool, error) {
+	var zero T
+	pageToken := ""
+	for {
+		page, err := list(ctx, pageToken)
+		if err != nil {
+			return zero, false, err
+		}
+
+		for _, item := range page.Items {
+			if match(item) {
+				return item, true, nil
+			}
+		}
+
+		if page.NextPageToken == "" {
+			return zero, false, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// collectPaged walks a paginated Zoom list and returns every item.
+func collectPaged[T any](ctx context.Context, list func(ctx context.Context, nextToken string) (*zoom.Page[T], error)) ([]T, error) {
+	var rv []T
+	pageToken := ""
+	for {
+		page, err := list(ctx, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, page.Items...)
+
+		if page.NextPageToken == "" {
+			return rv, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
@@ -32,6 +32,7 @@ func TestUserResourceTypeList(t *testing.T) {
 	user := &userResourceType{
 		resourceType: &v2.ResourceType{},
 		client:       cli.client,
+		statuses:     zoom.UserStatuses,
 	}
 	rs, _, _, err := user.List(ctx, &v2.ResourceId{}, &pagination.Token{})
 	assert.Nil(t, err)
@@ -44,12 +45,17 @@ func getClientForTesting(ctx context.Context) (*Zoom, error) {
 		return nil, err
 	}
 
-	token, err := zoom.RequestAccessToken(ctx, accountID, clientID, clientSecret)
+	endpoints, err := zoom.EndpointsFor(zoom.EnvironmentCommercial)
 	if err != nil {
+		return nil, err
+	}
+
+	tokenSource := zoom.NewTokenSource(httpClient, endpoints.OAuthURL, accountID, clientID, clientSecret)
+	if _, err := tokenSource.Token(ctx); err != nil {
 		return nil, fmt.Errorf("zoom-connector: failed to get token: %w", err)
 	}
 
 	return &Zoom{
-		client: zoom.NewClient(httpClient, token),
+		client: zoom.NewClient(httpClient, endpoints.APIBaseURL, tokenSource),
 	}, nil
 }
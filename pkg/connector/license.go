@@ -0,0 +1,362 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	grant "github.com/conductorone/baton-sdk/pkg/types/grant"
+	resource "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-zoom/pkg/zoom"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const assignedEntitlement = "assigned"
+
+// license is a Zoom user type or add-on plan. A user has exactly one user type, add-ons come on top of it.
+type license struct {
+	id          string
+	name        string
+	description string
+	// userType is set for user types.
+	userType zoom.UserType
+	// feature is the settings flag of an add-on, extra holds the other settings sent when turning it on.
+	feature string
+	extra   map[string]interface{}
+	has     func(zoom.UserFeatures) bool
+}
+
+var licenses = []license{
+	{
+		id:          "basic",
+		name:        "Basic",
+		description: "Free Zoom user with time-limited meetings.",
+		userType:    zoom.BasicUser,
+	},
+	{
+		id:          "licensed",
+		name:        "Licensed",
+		description: "Paid Zoom user seat. Add-ons require it.",
+		userType:    zoom.LicensedUser,
+	},
+	{
+		id:          "large_meeting",
+		name:        "Large Meeting",
+		description: "Large Meeting add-on, assigned with a capacity of 500 participants.",
+		feature:     "large_meeting",
+		extra:       map[string]interface{}{"large_meeting_capacity": 500},
+		has:         func(f zoom.UserFeatures) bool { return f.LargeMeeting },
+	},
+	{
+		id:          "webinar",
+		name:        "Webinar",
+		description: "Zoom Webinars add-on, assigned with a capacity of 500 attendees.",
+		feature:     "webinar",
+		extra:       map[string]interface{}{"webinar_capacity": 500},
+		has:         func(f zoom.UserFeatures) bool { return f.Webinar },
+	},
+	{
+		id:          "zoom_phone",
+		name:        "Zoom Phone",
+		description: "Zoom Phone add-on.",
+		feature:     "zoom_phone",
+		has:         func(f zoom.UserFeatures) bool { return f.ZoomPhone },
+	},
+	{
+		id:          "zoom_whiteboard",
+		name:        "Zoom Whiteboard",
+		description: "Zoom Whiteboard add-on.",
+		feature:     "zoom_whiteboard",
+		has:         func(f zoom.UserFeatures) bool { return f.ZoomWhiteboard },
+	},
+}
+
+func findLicense(id string) (license, bool) {
+	for _, l := range licenses {
+		if l.id == id {
+			return l, true
+		}
+	}
+
+	return license{}, false
+}
+
+func (l license) isUserType() bool {
+	return l.userType != 0
+}
+
+type licenseResourceType struct {
+	resourceType *v2.ResourceType
+	client       licenseAPI
+	protected    *protectedPrincipals
+	// statuses are the user statuses whose licenses are synced.
+	statuses []string
+}
+
+func (l *licenseResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return l.resourceType
+}
+
+// Create a new connector resource for a Zoom license.
+func licenseResource(lic license, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	return resource.NewResource(
+		lic.name,
+		resourceTypeLicense,
+		lic.id,
+		resource.WithDescription(lic.description),
+		resource.WithParentResourceID(parentResourceID),
+	)
+}
+
+func (l *licenseResourceType) List(_ context.Context, parentId *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	rv := make([]*v2.Resource, 0, len(licenses))
+	for _, lic := range licenses {
+		lr, err := licenseResource(lic, parentId)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, lr)
+	}
+
+	return rv, "", nil, nil
+}
+
+func (l *licenseResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	options := []ent.EntitlementOption{
+		ent.WithGrantableTo(resourceTypeUser),
+		ent.WithDescription(fmt.Sprintf("Has the %s license in zoom", resource.DisplayName)),
+		ent.WithDisplayName(fmt.Sprintf("%s license", resource.DisplayName)),
+	}
+
+	return []*v2.Entitlement{ent.NewAssignmentEntitlement(resource, assignedEntitlement, options...)}, "", nil, nil
+}
+
+// Grants of a license come from a walk of the users. User types come with the user list, add-ons need each
+// Licensed user's settings.
+func (l *licenseResourceType) Grants(ctx context.Context, resource *v2.Resource, token *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var rv []*v2.Grant
+
+	lic, ok := findLicense(resource.Id.Resource)
+	if !ok {
+		return nil, "", nil, fmt.Errorf("baton-zoom: unknown license %s", resource.Id.Resource)
+	}
+
+	users, pageToken, err := listUsersPage(ctx, l.client, l.statuses, token.Token)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if users == nil {
+		return nil, "", nil, nil
+	}
+
+	annos, err := parseResp(users.Response)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, user := range users.Items {
+		has, err := l.hasLicense(ctx, lic, user)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				continue
+			}
+			return nil, "", nil, err
+		}
+		if !has {
+			continue
+		}
+
+		ur, err := userResource(user, resource.Id)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, grant.NewGrant(resource, assignedEntitlement, ur.Id))
+	}
+
+	return rv, pageToken, annos, nil
+}
+
+func (l *licenseResourceType) hasLicense(ctx context.Context, lic license, user zoom.User) (bool, error) {
+	if lic.isUserType() {
+		return user.Type == int(lic.userType), nil
+	}
+	// Add-ons require a Licensed seat, other users' settings aren't worth a request.
+	if user.Type != int(zoom.LicensedUser) {
+		return false, nil
+	}
+
+	settings, err := l.client.GetUserSettings(ctx, user.ID)
+	if err != nil {
+		return false, fmt.Errorf("baton-zoom: failed to get settings of user %s: %w", user.ID, err)
+	}
+
+	return lic.has(settings.Feature), nil
+}
+
+// currentLicense looks up whether the user has the license right now.
+func (l *licenseResourceType) currentLicense(ctx context.Context, lic license, userId string) (zoom.User, bool, error) {
+	user, _, err := l.client.GetUser(ctx, userId)
+	if err != nil {
+		return zoom.User{}, false, fmt.Errorf("baton-zoom: failed to get user %s: %w", userId, err)
+	}
+
+	has, err := l.hasLicense(ctx, lic, user)
+	if err != nil {
+		return zoom.User{}, false, err
+	}
+
+	return user, has, nil
+}
+
+func (l *licenseResourceType) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) ([]*v2.Grant, annotations.Annotations, error) {
+	logger := ctxzap.Extract(ctx)
+
+	if principal.Id.ResourceType != resourceTypeUser.Id {
+		logger.Warn(
+			"baton-zoom: only users can be granted licenses",
+			zap.String("principal_type", principal.Id.ResourceType),
+			zap.String("principal_id", principal.Id.Resource),
+		)
+		return nil, nil, fmt.Errorf("baton-zoom: only users can be granted licenses")
+	}
+
+	lic, ok := findLicense(entitlement.Resource.Id.Resource)
+	if !ok {
+		return nil, nil, status.Errorf(codes.NotFound, "baton-zoom: unknown license %s", entitlement.Resource.Id.Resource)
+	}
+	userId := principal.Id.Resource
+
+	user, has, err := l.currentLicense(ctx, lic, userId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	grants := []*v2.Grant{grant.NewGrant(entitlement.Resource, assignedEntitlement, principal.Id)}
+	if has {
+		logger.Info(
+			"baton-zoom: user already has the license",
+			zap.String("license", lic.id),
+			zap.String("user_id", userId),
+		)
+		return grants, annotations.New(&v2.GrantAlreadyExists{}), nil
+	}
+
+	if lic.isUserType() {
+		// Moving a Licensed user to another type takes their seat away.
+		if user.Type == int(zoom.LicensedUser) {
+			err = l.protected.check(ctx, userId, "take the Licensed seat")
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		err = l.client.UpdateUser(ctx, userId, &zoom.UserUpdateBody{Type: lic.userType})
+	} else {
+		features := map[string]interface{}{lic.feature: true}
+		for k, v := range lic.extra {
+			features[k] = v
+		}
+		err = l.client.UpdateUserFeatures(ctx, userId, features)
+	}
+	if err != nil {
+		if zoom.IsOutOfLicenses(err) {
+			return nil, nil, status.Errorf(codes.ResourceExhausted, "baton-zoom: the account has no %s licenses left: %v", lic.name, err)
+		}
+		return nil, nil, fmt.Errorf("baton-zoom: failed to assign %s license: %w", lic.name, err)
+	}
+
+	if l.client.DryRun() {
+		return grants, withMarkers(nil, dryRunMarker), nil
+	}
+
+	_, has, err = l.currentLicense(ctx, lic, userId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-zoom: failed to confirm license assignment: %w", err)
+	}
+	if !has {
+		return nil, nil, fmt.Errorf("baton-zoom: user %s does not have the %s license after the grant", userId, lic.name)
+	}
+
+	return grants, nil, nil
+}
+
+func (l *licenseResourceType) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	logger := ctxzap.Extract(ctx)
+
+	entitlement := grant.Entitlement
+	principal := grant.Principal
+
+	if principal.Id.ResourceType != resourceTypeUser.Id {
+		logger.Warn(
+			"baton-zoom: only users can have licenses revoked",
+			zap.String("principal_type", principal.Id.ResourceType),
+			zap.String("principal_id", principal.Id.Resource),
+		)
+		return nil, fmt.Errorf("baton-zoom: only users can have licenses revoked")
+	}
+
+	lic, ok := findLicense(entitlement.Resource.Id.Resource)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "baton-zoom: unknown license %s", entitlement.Resource.Id.Resource)
+	}
+	if lic.userType == zoom.BasicUser {
+		return nil, status.Error(codes.FailedPrecondition, "baton-zoom: every Zoom user has a user type, grant another one instead of revoking Basic")
+	}
+	userId := principal.Id.Resource
+
+	err := l.protected.check(ctx, userId, fmt.Sprintf("revoke the %s license", lic.name))
+	if err != nil {
+		return nil, err
+	}
+
+	_, has, err := l.currentLicense(ctx, lic, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		logger.Info(
+			"baton-zoom: user no longer has the license",
+			zap.String("license", lic.id),
+			zap.String("user_id", userId),
+		)
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+
+	// Revoking the Licensed seat makes the user Basic.
+	if lic.isUserType() {
+		err = l.client.UpdateUser(ctx, userId, &zoom.UserUpdateBody{Type: zoom.BasicUser})
+	} else {
+		err = l.client.UpdateUserFeatures(ctx, userId, map[string]interface{}{lic.feature: false})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("baton-zoom: failed to remove %s license: %w", lic.name, err)
+	}
+
+	if l.client.DryRun() {
+		return withMarkers(nil, dryRunMarker), nil
+	}
+
+	_, has, err = l.currentLicense(ctx, lic, userId)
+	if err != nil {
+		return nil, fmt.Errorf("baton-zoom: failed to confirm license removal: %w", err)
+	}
+	if has {
+		return nil, fmt.Errorf("baton-zoom: user %s still has the %s license after the revoke", userId, lic.name)
+	}
+
+	return nil, nil
+}
+
+func licenseBuilder(client licenseAPI, protected *protectedPrincipals, statuses []string) *licenseResourceType {
+	return &licenseResourceType{
+		resourceType: resourceTypeLicense,
+		client:       client,
+		protected:    protected,
+		statuses:     statuses,
+	}
+}
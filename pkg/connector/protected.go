@@ -0,0 +1,105 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/conductorone/baton-zoom/pkg/zoom"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// accountOwnerId is the user ID Zoom resolves to the user the app acts for, the account owner.
+const accountOwnerId = "me"
+
+// protectedPrincipals guards users that must never lose access through the connector: the configured
+// user IDs and emails, holders of the Owner role and the account owner.
+type protectedPrincipals struct {
+	client principalAPI
+	// principals holds the configured user IDs and emails, lowercased.
+	principals map[string]bool
+
+	mu      sync.Mutex
+	ownerId string
+}
+
+func newProtectedPrincipals(client principalAPI, principals []string) *protectedPrincipals {
+	p := &protectedPrincipals{
+		client:     client,
+		principals: make(map[string]bool, len(principals)),
+	}
+	for _, principal := range principals {
+		principal = strings.ToLower(strings.TrimSpace(principal))
+		if principal != "" {
+			p.principals[principal] = true
+		}
+	}
+
+	return p
+}
+
+// check refuses a destructive operation against a protected user with a PermissionDenied error.
+// Users that no longer exist aren't protected, the operation is a no-op for them anyway.
+func (p *protectedPrincipals) check(ctx context.Context, userId string, operation string) error {
+	if p.principals[strings.ToLower(userId)] {
+		return p.refuse(ctx, userId, operation, "is on the protected principals list")
+	}
+
+	user, _, err := p.client.GetUser(ctx, userId)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		return fmt.Errorf("baton-zoom: failed to get user %s: %w", userId, err)
+	}
+
+	if user.Email != "" && p.principals[strings.ToLower(user.Email)] {
+		return p.refuse(ctx, userId, operation, "is on the protected principals list")
+	}
+	if user.RoleID == zoom.OwnerRoleID {
+		return p.refuse(ctx, userId, operation, "has the Owner role")
+	}
+
+	ownerId, err := p.accountOwner(ctx)
+	if err != nil {
+		return err
+	}
+	if user.ID == ownerId {
+		return p.refuse(ctx, userId, operation, "is the account owner")
+	}
+
+	return nil
+}
+
+func (p *protectedPrincipals) refuse(ctx context.Context, userId string, operation string, reason string) error {
+	ctxzap.Extract(ctx).Warn(
+		"baton-zoom: refused operation on protected principal",
+		zap.String("user_id", userId),
+		zap.String("operation", operation),
+		zap.String("reason", reason),
+	)
+
+	return status.Errorf(codes.PermissionDenied, "baton-zoom: refusing to %s, user %s %s", operation, userId, reason)
+}
+
+// accountOwner looks up the ID of the account owner once.
+func (p *protectedPrincipals) accountOwner(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ownerId != "" {
+		return p.ownerId, nil
+	}
+
+	owner, _, err := p.client.GetUser(ctx, accountOwnerId)
+	if err != nil {
+		return "", fmt.Errorf("baton-zoom: failed to get the account owner: %w", err)
+	}
+	p.ownerId = owner.ID
+
+	return p.ownerId, nil
+}
@@ -0,0 +1,168 @@
+package connector
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-zoom/pkg/zoom"
+	"github.com/conductorone/baton-zoom/pkg/zoom/zoomtest"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "re-record the replay fixtures from the fake Zoom server and rewrite the golden file")
+
+const (
+	replayFixtureDir = "testdata/replay"
+	replayGolden     = "testdata/replay.golden"
+)
+
+// TestReplaySync runs a full sync offline from recorded fixtures and compares the result to the golden file.
+// Run with -update to re-record the fixtures after changing what the connector requests.
+func TestReplaySync(t *testing.T) {
+	if *update {
+		recordReplayFixtures(t)
+	}
+
+	z, err := New(ctx, "account", "client", "secret", WithReplayDir(replayFixtureDir), WithUserDetails(true))
+	require.NoError(t, err)
+
+	got := syncSummary(t, z)
+	if *update {
+		require.NoError(t, os.WriteFile(replayGolden, []byte(got), 0o600))
+	}
+
+	want, err := os.ReadFile(replayGolden)
+	require.NoError(t, err)
+	require.Equal(t, string(want), got)
+}
+
+func recordReplayFixtures(t *testing.T) {
+	t.Helper()
+
+	srv := zoomtest.NewServer()
+	defer srv.Close()
+
+	srv.AddUser(zoom.User{Email: "owner@example.org", DisplayName: "Olivia Owner", RoleID: zoomtest.OwnerRoleID})
+	jane := srv.AddUser(zoom.User{
+		Email:             "jane@example.org",
+		DisplayName:       "Jane Doe",
+		Department:        "Engineering",
+		JobTitle:          "Staff Engineer",
+		Manager:           "owner@example.org",
+		Location:          "Berlin",
+		Timezone:          "Europe/Berlin",
+		CreatedAt:         "2023-04-01T09:00:00Z",
+		LastLoginTime:     "2024-05-06T07:08:09Z",
+		LastClientVersion: "6.0.2.33403(mac)",
+		Verified:          1,
+		PMI:               1234567890,
+		LoginTypes:        []int{100, 101},
+		EmployeeUniqueID:  "E-1001",
+		Type:              int(zoom.LicensedUser),
+		CustomAttributes:  []zoom.CustomAttribute{{Key: "cbf_cost_center", Name: "Cost Center", Value: "R&D"}},
+	})
+	john := srv.AddUser(zoom.User{Email: "john@example.org", DisplayName: "John Roe", RoleID: zoomtest.AdminRoleID})
+	srv.AddUser(zoom.User{Email: "former@example.org", DisplayName: "Former Employee", Status: zoom.UserStatusInactive})
+	srv.AddUser(zoom.User{Email: "invited@example.org", DisplayName: "Invited User", Status: zoom.UserStatusPending})
+	srv.SetUserFeatures(jane.ID, zoom.UserFeatures{Webinar: true, WebinarCapacity: 500, ZoomPhone: true})
+	group := srv.AddGroup(zoom.Group{Name: "Engineering"})
+	srv.AddGroupMember(group.ID, jane.ID)
+	srv.AddGroupMember(group.ID, john.ID)
+	srv.AddGroupAdmin(group.ID, john.ID)
+	iq := srv.AddRole(zoom.Role{Name: "Revenue Accelerator Admin", Type: zoom.RoleTypeIQ, Privileges: []string{"IQ:Read"}})
+	srv.AddRoleMember(iq.ID, jane.ID)
+	contacts := srv.AddContactGroup(zoom.ContactGroup{Name: "Support"})
+	srv.AddContactGroupMember(contacts.ID, zoom.GroupMember{ID: jane.ID, Name: "Jane Doe", Type: 1})
+	srv.AddContactGroupMember(contacts.ID, zoom.GroupMember{ID: group.ID, Name: "Engineering", Type: 2})
+
+	require.NoError(t, os.RemoveAll(replayFixtureDir))
+
+	z, err := New(
+		ctx,
+		zoomtest.AccountID,
+		zoomtest.ClientID,
+		zoomtest.ClientSecret,
+		WithEndpoints(srv.Endpoints()),
+		WithRecordDir(replayFixtureDir),
+		WithUserDetails(true),
+	)
+	require.NoError(t, err)
+
+	syncSummary(t, z)
+}
+
+// syncSummary lists every resource, entitlement and grant of the connector, one sorted line each.
+func syncSummary(t *testing.T, z *Zoom) string {
+	t.Helper()
+
+	var lines []string
+	for _, syncer := range z.ResourceSyncers(ctx) {
+		resources := collectPages(t, func(token *pagination.Token) ([]*v2.Resource, string, error) {
+			rs, next, _, err := syncer.List(ctx, nil, token)
+			return rs, next, err
+		})
+
+		for _, r := range resources {
+			lines = append(lines, fmt.Sprintf("resource %s:%s %q", r.Id.ResourceType, r.Id.Resource, r.DisplayName))
+
+			entitlements := collectPages(t, func(token *pagination.Token) ([]*v2.Entitlement, string, error) {
+				es, next, _, err := syncer.Entitlements(ctx, r, token)
+				return es, next, err
+			})
+			for _, e := range entitlements {
+				lines = append(lines, fmt.Sprintf("entitlement %s", e.Id))
+			}
+
+			grants := collectPages(t, func(token *pagination.Token) ([]*v2.Grant, string, error) {
+				gs, next, _, err := syncer.Grants(ctx, r, token)
+				return gs, next, err
+			})
+			for _, g := range grants {
+				lines = append(lines, fmt.Sprintf("grant %s %s:%s", g.Entitlement.Id, g.Principal.Id.ResourceType, g.Principal.Id.Resource))
+			}
+		}
+	}
+
+	sort.Strings(lines)
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func collectPages[T any](t *testing.T, fetch func(*pagination.Token) ([]T, string, error)) []T {
+	t.Helper()
+
+	var all []T
+	token := &pagination.Token{}
+	for {
+		items, next, err := fetch(token)
+		require.NoError(t, err)
+		all = append(all, items...)
+		if next == "" {
+			return all
+		}
+		token = &pagination.Token{Token: next}
+	}
+}
+
+func TestReplayFixturesAreSanitized(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(replayFixtureDir, "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		require.NoError(t, err)
+		require.NotContains(t, string(b), "@example.org", f)
+		require.NotContains(t, string(b), zoomtest.AccountID, f)
+		require.NotContains(t, string(b), "1234567890", f)
+		require.NotContains(t, string(b), `"Berlin"`, f)
+		require.NotContains(t, string(b), "R&D", f)
+	}
+}
@@ -0,0 +1,1611 @@
+package connector
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-zoom/pkg/zoom"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var errZoom = &zoom.APIError{StatusCode: http.StatusInternalServerError, Message: "Internal Error"}
+
+func apiError(code int) error {
+	return &zoom.APIError{StatusCode: http.StatusBadRequest, Code: code}
+}
+
+func testUser(t *testing.T, id string) *v2.Resource {
+	t.Helper()
+
+	r, err := userResource(zoom.User{ID: id, DisplayName: id}, nil)
+	require.NoError(t, err)
+
+	return r
+}
+
+func testGroup(t *testing.T, id string) *v2.Resource {
+	t.Helper()
+
+	r, err := groupResource(zoom.Group{ID: id, Name: id}, nil)
+	require.NoError(t, err)
+
+	return r
+}
+
+func testRole(t *testing.T, id string) *v2.Resource {
+	t.Helper()
+
+	r, err := roleResource(zoom.Role{ID: id, Name: id}, nil)
+	require.NoError(t, err)
+
+	return r
+}
+
+func TestGroupGrants(t *testing.T) {
+	tests := []struct {
+		name    string
+		api     *fakeAPI
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "members and admins",
+			api: &fakeAPI{
+				groupMembers: map[string][]zoom.User{"group-1": {{ID: "user-1"}, {ID: "user-2"}}},
+				groupAdmins:  map[string][]zoom.User{"group-1": {{ID: "user-2"}}},
+			},
+			want: []string{
+				"group:group-1:member user:user-1",
+				"group:group-1:member user:user-2",
+				"group:group-1:admin user:user-2",
+			},
+		},
+		{
+			name: "empty group",
+			api:  &fakeAPI{},
+		},
+		{
+			name:    "members fail",
+			api:     &fakeAPI{errs: map[string]error{"GetGroupMembers": errZoom}},
+			wantErr: errZoom,
+		},
+		{
+			name:    "admins fail",
+			api:     &fakeAPI{errs: map[string]error{"GetGroupAdmins": errZoom}},
+			wantErr: errZoom,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grants, next, _, err := groupBuilder(tt.api, newProtectedPrincipals(tt.api, nil)).Grants(ctx, testGroup(t, "group-1"), &pagination.Token{})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Empty(t, next)
+			require.Equal(t, tt.want, grantSummaries(grants))
+		})
+	}
+}
+
+func TestRoleGrants(t *testing.T) {
+	members := map[string][]zoom.User{"role-1": {{ID: "user-1"}, {ID: "user-2"}, {ID: "user-3"}}}
+
+	tests := []struct {
+		name      string
+		api       *fakeAPI
+		wantPages [][]string
+		wantErr   error
+	}{
+		{
+			name: "single page",
+			api:  &fakeAPI{roleMembers: members},
+			wantPages: [][]string{{
+				"role:role-1:member user:user-1",
+				"role:role-1:member user:user-2",
+				"role:role-1:member user:user-3",
+			}},
+		},
+		{
+			name: "paginated",
+			api:  &fakeAPI{roleMembers: members, pageSize: 2},
+			wantPages: [][]string{
+				{"role:role-1:member user:user-1", "role:role-1:member user:user-2"},
+				{"role:role-1:member user:user-3"},
+			},
+		},
+		{
+			name:    "api error",
+			api:     &fakeAPI{errs: map[string]error{"GetRoleMembers": errZoom}},
+			wantErr: errZoom,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roles := roleBuilder(tt.api, newProtectedPrincipals(tt.api, nil), defaultRoleName, false)
+			role := testRole(t, "role-1")
+
+			var pages [][]string
+			token := &pagination.Token{}
+			for {
+				grants, next, _, err := roles.Grants(ctx, role, token)
+				if tt.wantErr != nil {
+					require.ErrorIs(t, err, tt.wantErr)
+					return
+				}
+				require.NoError(t, err)
+				pages = append(pages, grantSummaries(grants))
+				if next == "" {
+					break
+				}
+				token = &pagination.Token{Token: next}
+			}
+			require.Equal(t, tt.wantPages, pages)
+		})
+	}
+}
+
+func TestRoleListAllTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		errs    map[string]error
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "every type",
+			want: []string{"role-member common exclusive", "role-0 common exclusive", "role-iq iq", "role-phone phone"},
+		},
+		{
+			name: "account without zoom phone",
+			errs: map[string]error{"GetRoles phone": apiError(zoom.ErrorCodeValidationFailed)},
+			want: []string{"role-member common exclusive", "role-0 common exclusive", "role-iq iq"},
+		},
+		{
+			name:    "common roles fail",
+			errs:    map[string]error{"GetRoles common": apiError(zoom.ErrorCodeValidationFailed)},
+			wantErr: true,
+		},
+		{
+			name:    "phone roles forbidden",
+			errs:    map[string]error{"GetRoles phone": &zoom.APIError{StatusCode: http.StatusForbidden, Code: 4711}},
+			wantErr: true,
+		},
+		{
+			name:    "iq roles fail",
+			errs:    map[string]error{"GetRoles iq": errZoom},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{
+				roles: []zoom.Role{
+					{ID: fakeMemberRoleID, Name: "Member", Type: zoom.RoleTypeCommon},
+					{ID: "role-0", Name: "Admin", Type: zoom.RoleTypeCommon},
+					{ID: "role-iq", Name: "IQ Admin", Type: zoom.RoleTypeIQ},
+					{ID: "role-phone", Name: "Phone Admin", Type: zoom.RoleTypePhone},
+				},
+				errs:     tt.errs,
+				pageSize: 1,
+			}
+			roles := roleBuilder(api, newProtectedPrincipals(api, nil), defaultRoleName, false)
+
+			var got []string
+			token := &pagination.Token{}
+			for {
+				listed, next, _, err := roles.List(ctx, nil, token)
+				if err != nil {
+					require.True(t, tt.wantErr, err)
+					return
+				}
+				for _, r := range listed {
+					trait, err := resource.GetRoleTrait(r)
+					require.NoError(t, err)
+					summary := r.Id.Resource + " " + trait.Profile.AsMap()["role_type"].(string)
+
+					// Only a user's common role is exclusive, IQ and phone roles are held next to it.
+					entitlements, _, _, err := roles.Entitlements(ctx, r, &pagination.Token{})
+					require.NoError(t, err)
+					memberAnnos := annotations.Annotations(entitlements[0].Annotations)
+					marker := &structpb.Struct{}
+					ok, err := memberAnnos.Pick(marker)
+					require.NoError(t, err)
+					if ok && marker.Fields[exclusiveGrantsMarker].GetBoolValue() {
+						summary += " exclusive"
+					}
+					got = append(got, summary)
+				}
+				if next == "" {
+					break
+				}
+				token = &pagination.Token{Token: next}
+			}
+			require.False(t, tt.wantErr)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIQRoleGrantAndRevoke(t *testing.T) {
+	api := &fakeAPI{
+		users:       map[string]zoom.User{"user-1": {ID: "user-1", RoleID: "role-0"}},
+		roleMembers: addMember(nil, "role-0", "user-1"),
+		roles:       append(slices.Clone(fakeRoles), zoom.Role{ID: "role-iq", Name: "IQ Admin", Type: zoom.RoleTypeIQ}),
+	}
+	roles := roleBuilder(api, newProtectedPrincipals(api, nil), defaultRoleName, false)
+	member := ent.NewPermissionEntitlement(testRole(t, "role-iq"), memberEntitlement)
+	user := testUser(t, "user-1")
+
+	// IQ roles are held next to the common role, nothing is displaced.
+	grants, _, err := roles.Grant(ctx, user, member)
+	require.NoError(t, err)
+	require.Equal(t, []string{"role:role-iq:member user:user-1"}, grantSummaries(grants))
+	require.Empty(t, previousRole(t, grants[0]))
+	require.Equal(t, "role-0", api.users["user-1"].RoleID)
+
+	_, err = roles.Revoke(ctx, &v2.Grant{Entitlement: member, Principal: user})
+	require.NoError(t, err)
+	require.Equal(t, []string{"AssignRole role-iq user-1", "UnassignRole role-iq user-1"}, api.calls)
+	require.Equal(t, "role-0", api.users["user-1"].RoleID)
+	require.Empty(t, api.roleMembers["role-iq"])
+}
+
+func TestRolePrivileges(t *testing.T) {
+	api := &fakeAPI{
+		roles: []zoom.Role{
+			{ID: "role-1", Name: "Auditor", Type: "common", Privileges: []string{"User:Read", "Recording:Read"}},
+		},
+		roleMembers: addMember(addMember(nil, "role-1", "user-1"), "role-1", "user-2"),
+		pageSize:    1,
+	}
+	roles := roleBuilder(api, newProtectedPrincipals(api, nil), defaultRoleName, false)
+
+	listed, _, _, err := roles.List(ctx, nil, &pagination.Token{})
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	role := listed[0]
+
+	trait, err := resource.GetRoleTrait(role)
+	require.NoError(t, err)
+	require.Equal(t, "common", trait.Profile.AsMap()["role_type"])
+	require.Equal(t, []interface{}{"User:Read", "Recording:Read"}, trait.Profile.AsMap()["privileges"])
+
+	entitlements, _, _, err := roles.Entitlements(ctx, role, &pagination.Token{})
+	require.NoError(t, err)
+	var slugs []string
+	for _, e := range entitlements {
+		slugs = append(slugs, e.Slug)
+	}
+	require.Equal(t, []string{memberEntitlement, "User:Read", "Recording:Read"}, slugs)
+	entitlementAnnos := annotations.Annotations(entitlements[1].Annotations)
+	require.True(t, entitlementAnnos.Contains(&v2.EntitlementImmutable{}))
+
+	// Privilege grants are only emitted once, on the first page of members.
+	first, next, _, err := roles.Grants(ctx, role, &pagination.Token{})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"role:role-1:member user:user-1",
+		"role:role-1:User:Read role:role-1",
+		"role:role-1:Recording:Read role:role-1",
+	}, grantSummaries(first))
+
+	expandable := &v2.GrantExpandable{}
+	privilegeAnnos := annotations.Annotations(first[1].Annotations)
+	ok, err := privilegeAnnos.Pick(expandable)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []string{"role:role-1:member"}, expandable.EntitlementIds)
+
+	second, _, _, err := roles.Grants(ctx, role, &pagination.Token{Token: next})
+	require.NoError(t, err)
+	require.Equal(t, []string{"role:role-1:member user:user-2"}, grantSummaries(second))
+
+	_, _, err = roles.Grant(ctx, testUser(t, "user-3"), entitlements[1])
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Empty(t, api.calls)
+}
+
+func TestGroupGrantAndRevoke(t *testing.T) {
+	group := testGroup(t, "group-1")
+	member := ent.NewAssignmentEntitlement(group, memberEntitlement)
+	admin := ent.NewAssignmentEntitlement(group, adminEntitlement)
+	user := testUser(t, "user-1")
+	otherGroup := testGroup(t, "group-2")
+
+	tests := []struct {
+		name        string
+		entitlement *v2.Entitlement
+		principal   *v2.Resource
+		// inGroup makes the user a member and admin of the group up front.
+		inGroup       bool
+		errs          map[string]error
+		wantGrant     []string
+		wantRevoke    []string
+		wantNoop      bool
+		wantGrantErr  bool
+		wantRevokeErr bool
+	}{
+		{
+			name:        "member",
+			entitlement: member,
+			principal:   user,
+			wantGrant:   []string{"AddGroupMembers group-1 user-1"},
+			wantRevoke:  []string{"DeleteGroupMember group-1 user-1"},
+		},
+		{
+			name:        "admin",
+			entitlement: admin,
+			principal:   user,
+			wantGrant:   []string{"AddGroupAdmins group-1 user-1"},
+			wantRevoke:  []string{"DeleteGroupAdmin group-1 user-1"},
+		},
+		{
+			name:          "group principal",
+			entitlement:   member,
+			principal:     otherGroup,
+			wantGrantErr:  true,
+			wantRevokeErr: true,
+		},
+		{
+			name:          "member api error",
+			entitlement:   member,
+			principal:     user,
+			errs:          map[string]error{"AddGroupMembers": errZoom, "DeleteGroupMember": errZoom},
+			wantGrantErr:  true,
+			wantRevokeErr: true,
+		},
+		{
+			name:          "admin api error",
+			entitlement:   admin,
+			principal:     user,
+			errs:          map[string]error{"AddGroupAdmins": errZoom, "DeleteGroupAdmin": errZoom},
+			wantGrantErr:  true,
+			wantRevokeErr: true,
+		},
+		{
+			name:        "member already in the requested state",
+			entitlement: member,
+			principal:   user,
+			inGroup:     true,
+			errs: map[string]error{
+				"AddGroupMembers":   apiError(zoom.ErrorCodeGroupMemberExists),
+				"DeleteGroupMember": apiError(zoom.ErrorCodeGroupMemberNotFound),
+			},
+			wantNoop: true,
+		},
+		{
+			name:        "admin already in the requested state",
+			entitlement: admin,
+			principal:   user,
+			inGroup:     true,
+			errs: map[string]error{
+				"AddGroupAdmins":   apiError(zoom.ErrorCodeGroupAdminExists),
+				"DeleteGroupAdmin": apiError(zoom.ErrorCodeGroupAdminNotFound),
+			},
+			wantNoop: true,
+		},
+		{
+			name:        "group does not exist",
+			entitlement: member,
+			principal:   user,
+			errs: map[string]error{
+				"AddGroupMembers":   apiError(zoom.ErrorCodeGroupNotFound),
+				"DeleteGroupMember": apiError(zoom.ErrorCodeGroupNotFound),
+			},
+			wantGrantErr:  true,
+			wantRevokeErr: true,
+		},
+		{
+			name:         "membership can't be confirmed",
+			entitlement:  admin,
+			principal:    user,
+			errs:         map[string]error{"GetGroupAdmins": errZoom},
+			wantGrant:    []string{"AddGroupAdmins group-1 user-1"},
+			wantRevoke:   []string{"DeleteGroupAdmin group-1 user-1"},
+			wantGrantErr: true,
+		},
+		{
+			name:         "member missing after the grant",
+			entitlement:  member,
+			principal:    user,
+			errs:         map[string]error{"AddGroupMembers": apiError(zoom.ErrorCodeGroupMemberExists)},
+			wantRevoke:   []string{"DeleteGroupMember group-1 user-1"},
+			wantGrantErr: true,
+		},
+		{
+			name:         "membership missing after the grant",
+			entitlement:  admin,
+			principal:    user,
+			errs:         map[string]error{"AddGroupAdmins": apiError(zoom.ErrorCodeGroupAdminExists)},
+			wantRevoke:   []string{"DeleteGroupAdmin group-1 user-1"},
+			wantGrantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{errs: tt.errs, users: map[string]zoom.User{"user-1": {ID: "user-1"}}}
+			if tt.inGroup {
+				api.groupMembers = addMember(nil, "group-1", "user-1")
+				api.groupAdmins = addMember(nil, "group-1", "user-1")
+			}
+			groups := groupBuilder(api, newProtectedPrincipals(api, nil))
+
+			grants, annos, err := groups.Grant(ctx, tt.principal, tt.entitlement)
+			require.Equal(t, tt.wantGrant, api.calls)
+			if tt.wantGrantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, []string{tt.entitlement.Id + " user:user-1"}, grantSummaries(grants))
+				require.Equal(t, tt.wantNoop, annos.Contains(&v2.GrantAlreadyExists{}))
+			}
+
+			api.calls = nil
+			annos, err = groups.Revoke(ctx, &v2.Grant{Entitlement: tt.entitlement, Principal: tt.principal})
+			require.Equal(t, tt.wantRevoke, api.calls)
+			if tt.wantRevokeErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantNoop, annos.Contains(&v2.GrantAlreadyRevoked{}))
+			}
+		})
+	}
+}
+
+func TestRoleGrantAndRevoke(t *testing.T) {
+	role := testRole(t, "role-1")
+	member := ent.NewPermissionEntitlement(role, memberEntitlement)
+
+	tests := []struct {
+		name      string
+		principal *v2.Resource
+		// currentRole is the role the user has before the grant, the user doesn't exist if empty.
+		currentRole   string
+		errs          map[string]error
+		wantGrant     []string
+		wantRevoke    []string
+		wantPrevious  string
+		wantNoop      bool
+		wantGrantErr  bool
+		wantRevokeErr bool
+	}{
+		{
+			name:         "replaces the previous role",
+			principal:    testUser(t, "user-1"),
+			currentRole:  "role-0",
+			wantGrant:    []string{"AssignRole role-1 user-1"},
+			wantRevoke:   []string{"UnassignRole role-1 user-1"},
+			wantPrevious: "role-0",
+		},
+		{
+			name:          "group principal",
+			principal:     testGroup(t, "group-1"),
+			wantGrantErr:  true,
+			wantRevokeErr: true,
+		},
+		{
+			name:          "api error",
+			principal:     testUser(t, "user-1"),
+			currentRole:   "role-1",
+			errs:          map[string]error{"AssignRole": errZoom, "UnassignRole": errZoom},
+			wantGrantErr:  true,
+			wantRevokeErr: true,
+		},
+		{
+			name:        "already in the requested state",
+			principal:   testUser(t, "user-1"),
+			currentRole: "role-1",
+			errs: map[string]error{
+				"AssignRole":   apiError(zoom.ErrorCodeRoleMemberExists),
+				"UnassignRole": apiError(zoom.ErrorCodeRoleMemberNotFound),
+			},
+			wantNoop: true,
+		},
+		{
+			name:      "user does not exist",
+			principal: testUser(t, "user-1"),
+			errs: map[string]error{
+				"UnassignRole": apiError(zoom.ErrorCodeUserNotFound),
+			},
+			wantGrantErr: true,
+			// A removed user lost their role with them.
+			wantNoop: true,
+		},
+		{
+			name:         "assignment missing after the grant",
+			principal:    testUser(t, "user-1"),
+			currentRole:  "role-0",
+			errs:         map[string]error{"AssignRole": apiError(zoom.ErrorCodeRoleMemberExists)},
+			wantGrantErr: true,
+			// The user still has their previous role, so the revoke finds nothing to do.
+			wantNoop: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{errs: tt.errs, users: map[string]zoom.User{}, roles: fakeRoles, pageSize: 1}
+			if tt.currentRole != "" {
+				api.users["user-1"] = zoom.User{ID: "user-1", RoleID: tt.currentRole}
+				// Put the user on the second page of the role.
+				api.roleMembers = addMember(addMember(nil, tt.currentRole, "user-0"), tt.currentRole, "user-1")
+			}
+			roles := roleBuilder(api, newProtectedPrincipals(api, nil), defaultRoleName, false)
+
+			grants, annos, err := roles.Grant(ctx, tt.principal, member)
+			require.Equal(t, tt.wantGrant, api.calls)
+			if tt.wantGrantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, []string{"role:role-1:member user:user-1"}, grantSummaries(grants))
+				require.Equal(t, tt.wantNoop, annos.Contains(&v2.GrantAlreadyExists{}))
+				require.Equal(t, tt.wantPrevious, previousRole(t, grants[0]))
+			}
+
+			api.calls = nil
+			annos, err = roles.Revoke(ctx, &v2.Grant{Entitlement: member, Principal: tt.principal})
+			require.Equal(t, tt.wantRevoke, api.calls)
+			if tt.wantRevokeErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantNoop, annos.Contains(&v2.GrantAlreadyRevoked{}))
+			}
+		})
+	}
+}
+
+func TestRoleRevokeLandsInDefaultRole(t *testing.T) {
+	tests := []struct {
+		name        string
+		defaultRole string
+		revokedRole string
+		currentRole string
+		wantCalls   []string
+		wantRole    string
+		wantNoop    bool
+		wantCode    codes.Code
+		wantErr     bool
+	}{
+		{
+			name:        "member",
+			defaultRole: defaultRoleName,
+			revokedRole: "role-1",
+			currentRole: "role-1",
+			wantCalls:   []string{"UnassignRole role-1 user-1"},
+			wantRole:    fakeMemberRoleID,
+		},
+		{
+			name:        "custom default role by name",
+			defaultRole: "basic",
+			revokedRole: "role-1",
+			currentRole: "role-1",
+			wantCalls:   []string{"UnassignRole role-1 user-1", "AssignRole role-basic user-1"},
+			wantRole:    "role-basic",
+		},
+		{
+			name:        "custom default role by id",
+			defaultRole: "role-basic",
+			revokedRole: "role-1",
+			currentRole: "role-1",
+			wantCalls:   []string{"UnassignRole role-1 user-1", "AssignRole role-basic user-1"},
+			wantRole:    "role-basic",
+		},
+		{
+			name:        "user moved to another role since the sync",
+			defaultRole: defaultRoleName,
+			revokedRole: "role-1",
+			currentRole: "role-0",
+			wantRole:    "role-0",
+			wantNoop:    true,
+		},
+		{
+			name:        "default role can't be revoked",
+			defaultRole: defaultRoleName,
+			revokedRole: fakeMemberRoleID,
+			currentRole: fakeMemberRoleID,
+			wantRole:    fakeMemberRoleID,
+			wantCode:    codes.FailedPrecondition,
+			wantErr:     true,
+		},
+		{
+			name:        "unknown default role",
+			defaultRole: "Nope",
+			revokedRole: "role-1",
+			currentRole: "role-1",
+			wantRole:    "role-1",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{
+				users:       map[string]zoom.User{"user-1": {ID: "user-1", RoleID: tt.currentRole}},
+				roleMembers: addMember(nil, tt.currentRole, "user-1"),
+				roles:       fakeRoles,
+				pageSize:    2,
+			}
+			role := testRole(t, tt.revokedRole)
+
+			annos, err := roleBuilder(api, newProtectedPrincipals(api, nil), tt.defaultRole, false).Revoke(ctx, &v2.Grant{
+				Entitlement: ent.NewPermissionEntitlement(role, memberEntitlement),
+				Principal:   testUser(t, "user-1"),
+			})
+			require.Equal(t, tt.wantCalls, api.calls)
+			require.Equal(t, tt.wantRole, api.users["user-1"].RoleID)
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.wantCode != codes.OK {
+					require.Equal(t, tt.wantCode, status.Code(err))
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantNoop, annos.Contains(&v2.GrantAlreadyRevoked{}))
+		})
+	}
+}
+
+func previousRole(t *testing.T, g *v2.Grant) string {
+	t.Helper()
+
+	metadata := &v2.GrantMetadata{}
+	annos := annotations.Annotations(g.Annotations)
+	ok, err := annos.Pick(metadata)
+	require.NoError(t, err)
+	if !ok {
+		return ""
+	}
+
+	return metadata.Metadata.AsMap()["previous_role_id"].(string)
+}
+
+func TestCreateAccount(t *testing.T) {
+	profile := map[string]interface{}{
+		"email":        "jane@example.com",
+		"first_name":   "Jane",
+		"last_name":    "Doe",
+		"display_name": "Jane Doe",
+	}
+	without := func(key string) map[string]interface{} {
+		p := map[string]interface{}{}
+		for k, v := range profile {
+			if k != key {
+				p[k] = v
+			}
+		}
+		return p
+	}
+	with := func(extra map[string]interface{}) map[string]interface{} {
+		p := maps.Clone(profile)
+		maps.Copy(p, extra)
+		return p
+	}
+
+	tests := []struct {
+		name        string
+		profile     map[string]interface{}
+		errs        map[string]error
+		wantCalls   []string
+		wantType    zoom.UserType
+		wantMessage string
+		wantErr     error
+	}{
+		{
+			name:      "created",
+			profile:   profile,
+			wantCalls: []string{"CreateUser create jane@example.com"},
+			wantType:  zoom.BasicUser,
+		},
+		{
+			name: "placed",
+			profile: with(map[string]interface{}{
+				createActionField: "ssoCreate",
+				userTypeField:     "Licensed",
+				departmentField:   "Engineering",
+				jobTitleField:     "Engineer",
+				initialGroupField: "engineering",
+				initialRoleField:  "role-1",
+			}),
+			wantCalls: []string{
+				"CreateUser ssoCreate jane@example.com",
+				"UpdateUser new-user dept=Engineering job_title=Engineer",
+				"AddGroupMembers group-1 new-user",
+				"AssignRole role-1 new-user",
+			},
+			wantType: zoom.LicensedUser,
+		},
+		{
+			name:    "unknown create action",
+			profile: with(map[string]interface{}{createActionField: "invite"}),
+			wantErr: errors.New("unknown create action invite"),
+		},
+		{
+			name:    "unknown user type",
+			profile: with(map[string]interface{}{userTypeField: "pro"}),
+			wantErr: errors.New("unknown user type pro"),
+		},
+		{
+			name:    "unknown group",
+			profile: with(map[string]interface{}{initialGroupField: "Sales"}),
+			wantErr: status.Error(codes.InvalidArgument, "baton-zoom: group Sales not found"),
+		},
+		{
+			name:    "unknown role",
+			profile: with(map[string]interface{}{initialRoleField: "Janitor"}),
+			wantErr: status.Error(codes.InvalidArgument, "baton-zoom: role Janitor not found"),
+		},
+		{
+			name:    "partial failure",
+			profile: with(map[string]interface{}{initialGroupField: "Engineering", initialRoleField: "Auditor"}),
+			errs:    map[string]error{"AddGroupMembers": errZoom},
+			wantCalls: []string{
+				"CreateUser create jane@example.com",
+				"AssignRole role-1 new-user",
+			},
+			wantType:    zoom.BasicUser,
+			wantMessage: "user created, but adding them to group Engineering failed",
+		},
+		{
+			name:    "out of licenses",
+			profile: with(map[string]interface{}{userTypeField: "licensed"}),
+			errs:    map[string]error{"CreateUser": apiError(zoom.ErrorCodeNotEnoughLicenses)},
+			wantErr: status.Error(codes.ResourceExhausted, "baton-zoom: the account has no licenses left for user jane@example.com: "+apiError(zoom.ErrorCodeNotEnoughLicenses).Error()),
+		},
+		{
+			name:    "missing email",
+			profile: without("email"),
+			wantErr: errors.New("email is required"),
+		},
+		{
+			name:    "missing display name",
+			profile: without("display_name"),
+			wantErr: errors.New("display name is required"),
+		},
+		{
+			name:    "api error",
+			profile: profile,
+			errs:    map[string]error{"CreateUser": errZoom},
+			wantErr: errZoom,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := structpb.NewStruct(tt.profile)
+			require.NoError(t, err)
+
+			api := &fakeAPI{errs: tt.errs, roles: fakeRoles, groups: []zoom.Group{{ID: "group-1", Name: "Engineering"}}}
+			resp, _, _, err := userBuilder(api, newProtectedPrincipals(api, nil), zoom.UserStatuses, deprovisioning{mode: DeprovisionDisassociate}, false, false).CreateAccount(ctx, &v2.AccountInfo{Profile: p}, nil)
+			require.Equal(t, tt.wantCalls, api.calls)
+			if tt.wantErr != nil {
+				require.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, int(tt.wantType), api.users["new-user"].Type)
+
+			if tt.wantMessage != "" {
+				result, ok := resp.(*v2.CreateAccountResponse_ActionRequiredResult)
+				require.True(t, ok)
+				require.Equal(t, "new-user", result.Resource.Id.Resource)
+				require.Contains(t, result.Message, tt.wantMessage)
+				return
+			}
+			result, ok := resp.(*v2.CreateAccountResponse_SuccessResult)
+			require.True(t, ok)
+			require.Equal(t, "new-user", result.Resource.Id.Resource)
+		})
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	tests := []struct {
+		name     string
+		api      *fakeAPI
+		wantCode codes.Code
+		wantErr  bool
+	}{
+		{
+			name: "deleted",
+			api:  &fakeAPI{users: map[string]zoom.User{"user-1": {ID: "user-1"}}},
+		},
+		{
+			name:     "unknown user",
+			api:      &fakeAPI{},
+			wantCode: codes.NotFound,
+			wantErr:  true,
+		},
+		{
+			name:    "still exists",
+			api:     &fakeAPI{users: map[string]zoom.User{"user-1": {ID: "user-1"}}, keepDeletedUsers: true},
+			wantErr: true,
+		},
+		{
+			name: "confirmation fails",
+			api: &fakeAPI{
+				users: map[string]zoom.User{"user-1": {ID: "user-1"}},
+				errs:  map[string]error{"GetUser": errZoom},
+			},
+			wantCode: codes.Internal,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := userBuilder(tt.api, newProtectedPrincipals(tt.api, nil), zoom.UserStatuses, deprovisioning{mode: DeprovisionDisassociate}, false, false).Delete(ctx, &v2.ResourceId{ResourceType: resourceTypeUser.Id, Resource: "user-1"})
+			if !tt.wantErr {
+				require.NoError(t, err)
+				require.NotContains(t, tt.api.users, "user-1")
+				return
+			}
+			require.Error(t, err)
+			if tt.wantCode != codes.OK {
+				require.Equal(t, tt.wantCode, status.Code(err))
+			}
+		})
+	}
+}
+
+func TestDeleteUserModes(t *testing.T) {
+	tests := []struct {
+		name        string
+		deprovision deprovisioning
+		user        zoom.User
+		keep        bool
+		wantCalls   []string
+		wantStatus  string
+		wantDeleted bool
+		wantCode    codes.Code
+		wantErr     bool
+	}{
+		{
+			name:        "disassociate",
+			deprovision: deprovisioning{mode: DeprovisionDisassociate},
+			wantCalls:   []string{"DeleteUser user-1 disassociate"},
+			wantDeleted: true,
+		},
+		{
+			name:        "delete with transfer",
+			deprovision: deprovisioning{mode: DeprovisionDelete, transferEmail: "boss@example.com", transferContent: []string{TransferRecordings, TransferMeetings}},
+			wantCalls:   []string{"DeleteUser user-1 delete to=boss@example.com meetings recordings"},
+			wantDeleted: true,
+		},
+		{
+			name:        "transfer to manager",
+			deprovision: deprovisioning{mode: DeprovisionDelete, transferEmail: TransferToManager, transferContent: TransferContentTypes},
+			user:        zoom.User{Manager: "manager@example.com"},
+			wantCalls:   []string{"DeleteUser user-1 delete to=manager@example.com meetings webinars recordings whiteboards"},
+			wantDeleted: true,
+		},
+		{
+			name:        "no manager",
+			deprovision: deprovisioning{mode: DeprovisionDelete, transferEmail: TransferToManager, transferContent: []string{TransferMeetings}},
+			wantCode:    codes.FailedPrecondition,
+			wantErr:     true,
+		},
+		{
+			name:        "deactivate",
+			deprovision: deprovisioning{mode: DeprovisionDeactivate},
+			user:        zoom.User{Status: zoom.UserStatusActive},
+			wantCalls:   []string{"UpdateUserStatus user-1 deactivate"},
+			wantStatus:  zoom.UserStatusInactive,
+		},
+		{
+			name:        "already inactive",
+			deprovision: deprovisioning{mode: DeprovisionDeactivate},
+			user:        zoom.User{Status: zoom.UserStatusInactive},
+			wantStatus:  zoom.UserStatusInactive,
+		},
+		{
+			name:        "still active",
+			deprovision: deprovisioning{mode: DeprovisionDeactivate},
+			user:        zoom.User{Status: zoom.UserStatusActive},
+			keep:        true,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.user.ID = "user-1"
+			api := &fakeAPI{users: map[string]zoom.User{"user-1": tt.user}, keepDeletedUsers: tt.keep}
+
+			_, err := userBuilder(api, newProtectedPrincipals(api, nil), zoom.UserStatuses, tt.deprovision, false, false).Delete(ctx, &v2.ResourceId{ResourceType: resourceTypeUser.Id, Resource: "user-1"})
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.wantCode != codes.OK {
+					require.Equal(t, tt.wantCode, status.Code(err))
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCalls, api.calls)
+			if tt.wantDeleted {
+				require.NotContains(t, api.users, "user-1")
+			} else {
+				require.Equal(t, tt.wantStatus, api.users["user-1"].Status)
+			}
+		})
+	}
+}
+
+func grantSummaries(grants []*v2.Grant) []string {
+	var rv []string
+	for _, g := range grants {
+		rv = append(rv, g.Entitlement.Id+" "+g.Principal.Id.ResourceType+":"+g.Principal.Id.Resource)
+	}
+
+	return rv
+}
+
+func TestRoleCreate(t *testing.T) {
+	roles := append(slices.Clone(fakeRoles), zoom.Role{ID: zoom.OwnerRoleID, Name: "Owner"})
+
+	newRole := func(t *testing.T, name string, profile map[string]interface{}) *v2.Resource {
+		t.Helper()
+
+		r, err := resource.NewRoleResource(name, resourceTypeRole, name, []resource.RoleTraitOption{resource.WithRoleProfile(profile)})
+		require.NoError(t, err)
+		r.Description = "Reviews things"
+
+		return r
+	}
+
+	tests := []struct {
+		name      string
+		role      string
+		profile   map[string]interface{}
+		dryRun    bool
+		wantCalls []string
+		wantType  string
+		wantCode  codes.Code
+	}{
+		{
+			name:      "new common role",
+			role:      "Reviewer",
+			profile:   map[string]interface{}{"privileges": []interface{}{"User:Read"}},
+			wantCalls: []string{"CreateRole Reviewer"},
+			wantType:  zoom.RoleTypeCommon,
+		},
+		{
+			name:      "new iq role",
+			role:      "Analyst",
+			profile:   map[string]interface{}{"role_type": zoom.RoleTypeIQ, "privileges": []interface{}{"IQ:Read"}},
+			wantCalls: []string{"CreateRole Analyst"},
+			wantType:  zoom.RoleTypeIQ,
+		},
+		{
+			name:     "existing custom role is left alone",
+			role:     "auditor",
+			profile:  map[string]interface{}{"privileges": []interface{}{"User:Read"}},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:     "existing custom role is left alone in a dry run",
+			role:     "auditor",
+			profile:  map[string]interface{}{"privileges": []interface{}{"User:Read"}},
+			dryRun:   true,
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:     "built-in role name is taken",
+			role:     "Owner",
+			profile:  map[string]interface{}{},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:     "built-in role name is taken in a dry run",
+			role:     "Owner",
+			profile:  map[string]interface{}{},
+			dryRun:   true,
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:     "unknown role type",
+			role:     "Reviewer",
+			profile:  map[string]interface{}{"role_type": "nope"},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{roles: roles, pageSize: 2, dryRun: tt.dryRun}
+
+			created, _, err := roleBuilder(api, newProtectedPrincipals(api, nil), defaultRoleName, false).Create(ctx, newRole(t, tt.role, tt.profile))
+			require.Equal(t, tt.wantCalls, api.calls)
+			if tt.wantCode != codes.OK {
+				require.Equal(t, tt.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+
+			privileges, err := rolePrivileges(created)
+			require.NoError(t, err)
+			require.Equal(t, []string{tt.profile["privileges"].([]interface{})[0].(string)}, privileges)
+
+			trait, err := resource.GetRoleTrait(created)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantType, trait.GetProfile().GetFields()["role_type"].GetStringValue())
+		})
+	}
+}
+
+func TestRoleDelete(t *testing.T) {
+	roles := append(slices.Clone(fakeRoles),
+		zoom.Role{ID: zoom.AdminRoleID, Name: "Admin"},
+		zoom.Role{ID: "role-iq", Name: "Analyst", Type: zoom.RoleTypeIQ},
+	)
+
+	tests := []struct {
+		name      string
+		role      string
+		members   []string
+		protected []string
+		force     bool
+		wantCalls []string
+		wantCode  codes.Code
+	}{
+		{
+			name:      "empty custom role",
+			role:      "role-1",
+			wantCalls: []string{"DeleteRole role-1"},
+		},
+		{
+			name: "already deleted",
+			role: "role-gone",
+		},
+		{
+			name:     "built-in role",
+			role:     zoom.AdminRoleID,
+			force:    true,
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "role with members",
+			role:     "role-1",
+			members:  []string{"user-1"},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:      "forced common role moves members to the default role",
+			role:      "role-1",
+			members:   []string{"user-1", "user-2"},
+			force:     true,
+			wantCalls: []string{"AssignRole role-member user-1", "AssignRole role-member user-2", "DeleteRole role-1"},
+		},
+		{
+			name:      "forced iq role unassigns members",
+			role:      "role-iq",
+			members:   []string{"user-1"},
+			force:     true,
+			wantCalls: []string{"UnassignRole role-iq user-1", "DeleteRole role-iq"},
+		},
+		{
+			name:      "forced delete with a protected member",
+			role:      "role-1",
+			members:   []string{"user-1", "user-2"},
+			protected: []string{"user-2"},
+			force:     true,
+			wantCode:  codes.PermissionDenied,
+		},
+		{
+			name:     "forced delete with the account owner as member",
+			role:     "role-iq",
+			members:  []string{fakeOwnerID},
+			force:    true,
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "default role",
+			role:     fakeMemberRoleID,
+			members:  []string{"user-1"},
+			force:    true,
+			wantCode: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{users: map[string]zoom.User{}, roles: roles, pageSize: 1}
+			for _, id := range tt.members {
+				api.users[id] = zoom.User{ID: id, RoleID: tt.role}
+				api.roleMembers = addMember(api.roleMembers, tt.role, id)
+			}
+
+			_, err := roleBuilder(api, newProtectedPrincipals(api, tt.protected), defaultRoleName, tt.force).Delete(ctx, &v2.ResourceId{ResourceType: resourceTypeRole.Id, Resource: tt.role})
+			require.Equal(t, tt.wantCalls, api.calls)
+			if tt.wantCode != codes.OK {
+				require.Equal(t, tt.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			for _, id := range tt.members {
+				require.NotContains(t, api.roleMembers[tt.role], zoom.User{ID: id})
+			}
+		})
+	}
+}
+
+func TestProtectedPrincipals(t *testing.T) {
+	users := map[string]zoom.User{
+		"user-1":    {ID: "user-1", Email: "jane@example.com", RoleID: "role-1"},
+		"owner":     {ID: "owner", Email: "owner@example.com", RoleID: zoom.OwnerRoleID},
+		"co-owner":  {ID: "co-owner", Email: "co-owner@example.com", RoleID: zoom.OwnerRoleID},
+		"admin-1":   {ID: "admin-1", Email: "admin@example.com", RoleID: zoom.AdminRoleID},
+		"the-owner": {ID: "the-owner", Email: "boss@example.com", RoleID: "role-1"},
+	}
+	roles := append(slices.Clone(fakeRoles), zoom.Role{ID: zoom.AdminRoleID, Name: "Admin"})
+
+	revokeGroup := func(api *fakeAPI, protected *protectedPrincipals, userId string) error {
+		group := testGroup(t, "group-1")
+		_, err := groupBuilder(api, protected).Revoke(ctx, &v2.Grant{
+			Entitlement: ent.NewPermissionEntitlement(group, memberEntitlement),
+			Principal:   testUser(t, userId),
+		})
+		return err
+	}
+	revokeRole := func(roleId string) func(api *fakeAPI, protected *protectedPrincipals, userId string) error {
+		return func(api *fakeAPI, protected *protectedPrincipals, userId string) error {
+			role := testRole(t, roleId)
+			_, err := roleBuilder(api, protected, defaultRoleName, false).Revoke(ctx, &v2.Grant{
+				Entitlement: ent.NewPermissionEntitlement(role, memberEntitlement),
+				Principal:   testUser(t, userId),
+			})
+			return err
+		}
+	}
+	deleteUser := func(api *fakeAPI, protected *protectedPrincipals, userId string) error {
+		_, err := userBuilder(api, protected, zoom.UserStatuses, deprovisioning{mode: DeprovisionDisassociate}, false, false).Delete(ctx, &v2.ResourceId{ResourceType: resourceTypeUser.Id, Resource: userId})
+		return err
+	}
+
+	tests := []struct {
+		name      string
+		protected []string
+		user      string
+		op        func(api *fakeAPI, protected *protectedPrincipals, userId string) error
+		wantDeny  bool
+	}{
+		{name: "unprotected user", user: "user-1", op: deleteUser},
+		{name: "listed id", protected: []string{"user-1"}, user: "user-1", op: deleteUser, wantDeny: true},
+		{name: "listed email", protected: []string{" JANE@example.com "}, user: "user-1", op: revokeGroup, wantDeny: true},
+		{name: "owner role", user: "co-owner", op: revokeGroup, wantDeny: true},
+		{name: "account owner", user: "the-owner", op: revokeRole("role-1"), wantDeny: true},
+		{name: "account owner can't be deleted", user: "the-owner", op: deleteUser, wantDeny: true},
+		{name: "last admin", user: "admin-1", op: revokeRole(zoom.AdminRoleID), wantDeny: true},
+		{name: "unprotected role revoke", user: "user-1", op: revokeRole("role-1")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{users: map[string]zoom.User{}, roles: roles, groupMembers: map[string][]zoom.User{}}
+			for id, u := range users {
+				api.users[id] = u
+				api.roleMembers = addMember(api.roleMembers, u.RoleID, id)
+				api.groupMembers["group-1"] = append(api.groupMembers["group-1"], zoom.User{ID: id})
+			}
+			api.users[accountOwnerId] = users["the-owner"]
+			err := tt.op(api, newProtectedPrincipals(api, tt.protected), tt.user)
+			if tt.wantDeny {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+				require.Empty(t, api.calls)
+				return
+			}
+			require.NoError(t, err)
+			require.NotEmpty(t, api.calls)
+		})
+	}
+}
+
+func TestRevokeAdminFromFormerAdmin(t *testing.T) {
+	api := &fakeAPI{
+		users: map[string]zoom.User{
+			"user-1":  {ID: "user-1", RoleID: "role-1"},
+			"admin-1": {ID: "admin-1", RoleID: zoom.AdminRoleID},
+		},
+		roles:       append(slices.Clone(fakeRoles), zoom.Role{ID: zoom.AdminRoleID, Name: "Admin"}),
+		roleMembers: addMember(addMember(nil, "role-1", "user-1"), zoom.AdminRoleID, "admin-1"),
+	}
+
+	// The last Admin is someone else, the user already lost the role and nothing is refused.
+	annos, err := roleBuilder(api, newProtectedPrincipals(api, nil), defaultRoleName, false).Revoke(ctx, &v2.Grant{
+		Entitlement: ent.NewPermissionEntitlement(testRole(t, zoom.AdminRoleID), memberEntitlement),
+		Principal:   testUser(t, "user-1"),
+	})
+	require.NoError(t, err)
+	require.True(t, annos.Contains(&v2.GrantAlreadyRevoked{}))
+	require.Empty(t, api.calls)
+}
+
+func TestGroupRevokeFromRemovedUser(t *testing.T) {
+	api := &fakeAPI{
+		users: map[string]zoom.User{},
+		// Zoom answers removing a user that no longer exists with "User does not exist".
+		errs: map[string]error{"DeleteGroupMember": userNotFound("gone")},
+	}
+	annos, err := groupBuilder(api, newProtectedPrincipals(api, nil)).Revoke(ctx, &v2.Grant{
+		Entitlement: ent.NewPermissionEntitlement(testGroup(t, "group-1"), memberEntitlement),
+		Principal:   testUser(t, "gone"),
+	})
+	require.NoError(t, err)
+	require.True(t, annos.Contains(&v2.GrantAlreadyRevoked{}))
+	require.Empty(t, api.calls)
+}
+
+func TestUserResourceProfile(t *testing.T) {
+	r, err := userResource(zoom.User{
+		ID:                "user-1",
+		Email:             "jane@example.com",
+		DisplayName:       "Jane Doe",
+		Status:            "active",
+		Department:        "Engineering",
+		JobTitle:          "Staff Engineer",
+		Manager:           "boss@example.com",
+		Location:          "Berlin",
+		Timezone:          "Europe/Berlin",
+		CreatedAt:         "2023-04-01T09:00:00Z",
+		LastLoginTime:     "2024-05-06T07:08:09Z",
+		LastClientVersion: "6.0.2.33403(mac)",
+		Verified:          1,
+		PMI:               1234567890,
+		LoginTypes:        []int{100, 101},
+		EmployeeUniqueID:  "E-1001",
+		CustomAttributes:  []zoom.CustomAttribute{{Key: "cbf_1", Name: "Cost Center", Value: "R&D"}},
+	}, nil)
+	require.NoError(t, err)
+
+	trait, err := resource.GetUserTrait(r)
+	require.NoError(t, err)
+
+	profile := trait.GetProfile().AsMap()
+	require.Equal(t, "Engineering", profile["department"])
+	require.Equal(t, "Staff Engineer", profile["job_title"])
+	require.Equal(t, "boss@example.com", profile["manager"])
+	require.Equal(t, "Berlin", profile["location"])
+	require.Equal(t, "Europe/Berlin", profile["timezone"])
+	require.Equal(t, "6.0.2.33403(mac)", profile["last_client_version"])
+	require.Equal(t, true, profile["verified"])
+	require.Equal(t, float64(1234567890), profile["pmi"])
+	require.Equal(t, []interface{}{float64(100), float64(101)}, profile["login_types"])
+	require.Equal(t, "E-1001", profile["employee_unique_id"])
+	require.Equal(t, "R&D", profile["custom_attribute_cost_center"])
+
+	require.Equal(t, "2023-04-01T09:00:00Z", trait.GetCreatedAt().AsTime().Format(time.RFC3339))
+	require.Equal(t, "2024-05-06T07:08:09Z", trait.GetLastLogin().AsTime().Format(time.RFC3339))
+	require.Equal(t, []string{"E-1001"}, trait.GetEmployeeIds())
+
+	// Members listed by groups and roles carry no details.
+	r, err = userResource(zoom.User{ID: "user-2"}, nil)
+	require.NoError(t, err)
+	trait, err = resource.GetUserTrait(r)
+	require.NoError(t, err)
+	require.Nil(t, trait.GetCreatedAt())
+	require.NotContains(t, trait.GetProfile().AsMap(), "department")
+}
+
+func TestUserListStatuses(t *testing.T) {
+	users := map[string]zoom.User{
+		"user-1": {ID: "user-1", Status: zoom.UserStatusActive},
+		"user-2": {ID: "user-2", Status: zoom.UserStatusActive},
+		"user-3": {ID: "user-3", Status: zoom.UserStatusActive},
+		"user-4": {ID: "user-4", Status: zoom.UserStatusInactive},
+		"user-5": {ID: "user-5", Status: zoom.UserStatusPending},
+	}
+
+	tests := []struct {
+		name     string
+		statuses []string
+		errs     map[string]error
+		want     []string
+		wantErr  error
+	}{
+		{
+			name:     "all statuses",
+			statuses: zoom.UserStatuses,
+			want:     []string{"user-1", "user-2", "user-3", "user-4", "user-5"},
+		},
+		{
+			name:     "inactive and pending only",
+			statuses: []string{zoom.UserStatusInactive, zoom.UserStatusPending},
+			want:     []string{"user-4", "user-5"},
+		},
+		{
+			name: "no statuses",
+		},
+		{
+			name:     "listing fails",
+			statuses: zoom.UserStatuses,
+			errs:     map[string]error{"GetUsers pending": errZoom},
+			wantErr:  errZoom,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{users: users, pageSize: 2, errs: tt.errs}
+			syncer := userBuilder(api, newProtectedPrincipals(api, nil), tt.statuses, deprovisioning{mode: DeprovisionDisassociate}, false, false)
+
+			var got []string
+			token := &pagination.Token{}
+			for {
+				rs, next, _, err := syncer.List(ctx, nil, token)
+				if err != nil {
+					require.ErrorIs(t, err, tt.wantErr)
+					return
+				}
+				for _, r := range rs {
+					got = append(got, r.Id.Resource)
+				}
+				if next == "" {
+					break
+				}
+				token = &pagination.Token{Token: next}
+			}
+			require.Nil(t, tt.wantErr)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUserListDetails(t *testing.T) {
+	users := map[string]zoom.User{
+		"user-1": {ID: "user-1", Status: zoom.UserStatusActive},
+		"user-2": {ID: "user-2", Status: zoom.UserStatusActive},
+	}
+
+	tests := []struct {
+		name      string
+		details   bool
+		errs      map[string]error
+		wantReads []string
+	}{
+		{
+			name: "listed fields only",
+		},
+		{
+			name:      "details of every user",
+			details:   true,
+			wantReads: []string{"GetUser user-1", "GetUser user-2"},
+		},
+		{
+			name:    "details can't be got",
+			details: true,
+			errs:    map[string]error{"GetUser": errZoom},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{users: users, errs: tt.errs}
+			syncer := userBuilder(api, newProtectedPrincipals(api, nil), []string{zoom.UserStatusActive}, deprovisioning{mode: DeprovisionDisassociate}, false, tt.details)
+
+			rs, _, _, err := syncer.List(ctx, nil, &pagination.Token{})
+			require.NoError(t, err)
+			require.Len(t, rs, 2)
+
+			var reads []string
+			for _, read := range api.reads {
+				if strings.HasPrefix(read, "GetUser ") {
+					reads = append(reads, read)
+				}
+			}
+			require.Equal(t, tt.wantReads, reads)
+		})
+	}
+}
+
+func testLicense(t *testing.T, id string) *v2.Resource {
+	t.Helper()
+
+	lic, ok := findLicense(id)
+	require.True(t, ok)
+	r, err := licenseResource(lic, nil)
+	require.NoError(t, err)
+
+	return r
+}
+
+func TestLicenseGrants(t *testing.T) {
+	api := &fakeAPI{
+		users: map[string]zoom.User{
+			"user-1": {ID: "user-1", Status: zoom.UserStatusActive, Type: int(zoom.BasicUser)},
+			"user-2": {ID: "user-2", Status: zoom.UserStatusActive, Type: int(zoom.LicensedUser)},
+			"user-3": {ID: "user-3", Status: zoom.UserStatusPending, Type: int(zoom.LicensedUser)},
+		},
+		features: map[string]zoom.UserFeatures{
+			"user-2": {Webinar: true, ZoomPhone: true},
+			"user-3": {Webinar: true},
+		},
+		pageSize: 1,
+	}
+
+	tests := []struct {
+		license string
+		want    []string
+		// wantSettingsReads counts the users whose settings are fetched, only Licensed users can hold add-ons.
+		wantSettingsReads int
+	}{
+		{
+			license: "basic",
+			want:    []string{"license:basic:assigned user:user-1"},
+		},
+		{
+			license: "licensed",
+			want:    []string{"license:licensed:assigned user:user-2", "license:licensed:assigned user:user-3"},
+		},
+		{
+			license:           "large_meeting",
+			wantSettingsReads: 2,
+		},
+		{
+			license:           "webinar",
+			want:              []string{"license:webinar:assigned user:user-2", "license:webinar:assigned user:user-3"},
+			wantSettingsReads: 2,
+		},
+		{
+			license:           "zoom_phone",
+			want:              []string{"license:zoom_phone:assigned user:user-2"},
+			wantSettingsReads: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.license, func(t *testing.T) {
+			api.reads = nil
+			licenses := licenseBuilder(api, newProtectedPrincipals(api, nil), zoom.UserStatuses)
+
+			var got []string
+			token := &pagination.Token{}
+			for {
+				grants, next, _, err := licenses.Grants(ctx, testLicense(t, tt.license), token)
+				require.NoError(t, err)
+				got = append(got, grantSummaries(grants)...)
+				if next == "" {
+					break
+				}
+				token = &pagination.Token{Token: next}
+			}
+			require.ElementsMatch(t, tt.want, got)
+
+			var settingsReads, listings int
+			for _, read := range api.reads {
+				switch {
+				case strings.HasPrefix(read, "GetUserSettings "):
+					settingsReads++
+				case strings.HasPrefix(read, "GetUsers "):
+					listings++
+				}
+			}
+			require.Equal(t, tt.wantSettingsReads, settingsReads)
+			// One page per user plus an empty one for inactive users.
+			require.Equal(t, 4, listings)
+		})
+	}
+}
+
+func TestLicenseGrantAndRevoke(t *testing.T) {
+	tests := []struct {
+		name           string
+		license        string
+		userType       zoom.UserType
+		features       zoom.UserFeatures
+		errs           map[string]error
+		wantGrant      []string
+		wantGrantNoop  bool
+		wantGrantCode  codes.Code
+		wantRevoke     []string
+		wantRevokeNoop bool
+		wantRevokeCode codes.Code
+	}{
+		{
+			name:       "licensed seat",
+			license:    "licensed",
+			userType:   zoom.BasicUser,
+			wantGrant:  []string{"UpdateUser user-1 2"},
+			wantRevoke: []string{"UpdateUser user-1 1"},
+		},
+		{
+			name:          "already licensed",
+			license:       "licensed",
+			userType:      zoom.LicensedUser,
+			wantGrantNoop: true,
+			wantRevoke:    []string{"UpdateUser user-1 1"},
+		},
+		{
+			name:          "no seats left",
+			license:       "licensed",
+			userType:      zoom.BasicUser,
+			errs:          map[string]error{"UpdateUser": apiError(zoom.ErrorCodeNotEnoughLicenses)},
+			wantGrantCode: codes.ResourceExhausted,
+			// The user never got the seat.
+			wantRevokeNoop: true,
+		},
+		{
+			name:           "basic can't be revoked",
+			license:        "basic",
+			userType:       zoom.LicensedUser,
+			wantGrant:      []string{"UpdateUser user-1 1"},
+			wantRevokeCode: codes.FailedPrecondition,
+		},
+		{
+			name:       "add-on with capacity",
+			license:    "webinar",
+			userType:   zoom.LicensedUser,
+			wantGrant:  []string{"UpdateUserFeatures user-1 webinar=true webinar_capacity=500"},
+			wantRevoke: []string{"UpdateUserFeatures user-1 webinar=false"},
+		},
+		{
+			name:          "add-on already assigned",
+			license:       "zoom_phone",
+			userType:      zoom.LicensedUser,
+			features:      zoom.UserFeatures{ZoomPhone: true},
+			wantGrantNoop: true,
+			wantRevoke:    []string{"UpdateUserFeatures user-1 zoom_phone=false"},
+		},
+		{
+			name:           "add-on out of seats",
+			license:        "zoom_phone",
+			userType:       zoom.LicensedUser,
+			errs:           map[string]error{"UpdateUserFeatures": apiError(zoom.ErrorCodeNotEnoughLicenses)},
+			wantGrantCode:  codes.ResourceExhausted,
+			wantRevokeNoop: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{
+				users:    map[string]zoom.User{"user-1": {ID: "user-1", Type: int(tt.userType)}},
+				features: map[string]zoom.UserFeatures{"user-1": tt.features},
+				errs:     tt.errs,
+			}
+			licenses := licenseBuilder(api, newProtectedPrincipals(api, nil), zoom.UserStatuses)
+			entitlement := ent.NewAssignmentEntitlement(testLicense(t, tt.license), assignedEntitlement)
+
+			grants, annos, err := licenses.Grant(ctx, testUser(t, "user-1"), entitlement)
+			require.Equal(t, tt.wantGrant, api.calls)
+			if tt.wantGrantCode != codes.OK {
+				require.Equal(t, tt.wantGrantCode, status.Code(err))
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, []string{"license:" + tt.license + ":assigned user:user-1"}, grantSummaries(grants))
+				require.Equal(t, tt.wantGrantNoop, annos.Contains(&v2.GrantAlreadyExists{}))
+			}
+
+			api.calls = nil
+			annos, err = licenses.Revoke(ctx, &v2.Grant{Entitlement: entitlement, Principal: testUser(t, "user-1")})
+			require.Equal(t, tt.wantRevoke, api.calls)
+			if tt.wantRevokeCode != codes.OK {
+				require.Equal(t, tt.wantRevokeCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantRevokeNoop, annos.Contains(&v2.GrantAlreadyRevoked{}))
+		})
+	}
+}
@@ -3,6 +3,9 @@ package connector
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
@@ -13,6 +16,8 @@ import (
 	"github.com/conductorone/baton-zoom/pkg/zoom"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -20,9 +25,21 @@ const (
 	adminEntitlement  = "admin"
 )
 
+// defaultRoleName is the built-in role Zoom gives users without any other role.
+const defaultRoleName = "Member"
+
 type roleResourceType struct {
 	resourceType *v2.ResourceType
-	client       *zoom.Client
+	client       roleAPI
+	protected    *protectedPrincipals
+	// defaultRole is the name or ID of the role users land in when their role is revoked.
+	defaultRole string
+
+	// forceDelete lets Delete remove roles that still have members, moving them out first.
+	forceDelete bool
+
+	mu            sync.Mutex
+	defaultRoleId string
 }
 
 func (r *roleResourceType) ResourceType(_ context.Context) *v2.ResourceType {
@@ -31,9 +48,16 @@ func (r *roleResourceType) ResourceType(_ context.Context) *v2.ResourceType {
 
 // Create a new connector resource for a Zoom role.
 func roleResource(role zoom.Role, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	privileges := make([]interface{}, 0, len(role.Privileges))
+	for _, privilege := range role.Privileges {
+		privileges = append(privileges, privilege)
+	}
+
 	profile := map[string]interface{}{
-		"role_name": role.Name,
-		"role_id":   role.ID,
+		"role_name":  role.Name,
+		"role_id":    role.ID,
+		"role_type":  role.Type,
+		"privileges": privileges,
 	}
 
 	roleTraitOptions := []resource.RoleTraitOption{
@@ -57,19 +81,65 @@ func roleResource(role zoom.Role, parentResourceID *v2.ResourceId) (*v2.Resource
 func (r *roleResourceType) List(ctx context.Context, parentId *v2.ResourceId, token *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
 	var rv []*v2.Resource
 
-	roles, resp, err := r.client.GetRoles(ctx)
+	// Zoom lists each role type separately, the bag holds one page state per type still to list.
+	bag := &pagination.Bag{}
+	err := bag.Unmarshal(token.Token)
 	if err != nil {
 		return nil, "", nil, err
 	}
-	resp.Body.Close()
 
-	annos, err := parseResp(resp)
+	if bag.Current() == nil {
+		for i := len(zoom.RoleTypes) - 1; i >= 0; i-- {
+			bag.Push(pagination.PageState{
+				ResourceTypeID: resourceTypeRole.Id,
+				ResourceID:     zoom.RoleTypes[i],
+			})
+		}
+	}
+
+	roleType := bag.ResourceID()
+	roles, err := r.client.GetRoles(ctx, roleType, bag.PageToken())
+	if err != nil {
+		// Accounts without Zoom Phone or Revenue Accelerator can't list those roles. Anything else, a missing
+		// scope included, fails the sync rather than silently dropping the roles.
+		if roleType == zoom.RoleTypeCommon || !zoom.IsFeatureNotEnabled(err) {
+			return nil, "", nil, fmt.Errorf("baton-zoom: failed to list %s roles: %w", roleType, err)
+		}
+		ctxzap.Extract(ctx).Info(
+			"baton-zoom: skipping role type not available for the account",
+			zap.String("role_type", roleType),
+			zap.Error(err),
+		)
+		roles = &zoom.Page[zoom.Role]{}
+	}
+
+	err = bag.Next(roles.NextPageToken)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	for _, role := range roles {
+	pageToken, err := bag.Marshal()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	annos, err := parseResp(roles.Response)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, role := range roles.Items {
+		// Privileges are only returned when getting a single role.
+		details, err := r.client.GetRole(ctx, role.ID)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("baton-zoom: failed to get role %s: %w", role.ID, err)
+		}
+
 		roleCopy := role
+		roleCopy.Privileges = details.Privileges
+		if roleCopy.Type == "" {
+			roleCopy.Type = roleType
+		}
 		rr, err := roleResource(roleCopy, parentId)
 		if err != nil {
 			return nil, "", nil, err
@@ -77,7 +147,7 @@ func (r *roleResourceType) List(ctx context.Context, parentId *v2.ResourceId, to
 		rv = append(rv, rr)
 	}
 
-	return rv, "", annos, nil
+	return rv, pageToken, annos, nil
 }
 
 func (r *roleResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
@@ -89,12 +159,60 @@ func (r *roleResourceType) Entitlements(_ context.Context, resource *v2.Resource
 		ent.WithDisplayName(fmt.Sprintf("%s role %s", resource.DisplayName, memberEntitlement)),
 	}
 
+	privileges, err := rolePrivileges(resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	roleType, err := roleTypeOf(resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if isExclusiveRole(&zoom.Role{Type: roleType}) {
+		roleOptions = append(roleOptions, ent.WithAnnotation(markerAnnotation(exclusiveGrantsMarker)))
+	}
+
 	en := ent.NewPermissionEntitlement(resource, memberEntitlement, roleOptions...)
 	rv = append(rv, en)
 
+	// Privileges come with the role and can't be granted on their own.
+	for _, privilege := range privileges {
+		privilegeOptions := []ent.EntitlementOption{
+			ent.WithGrantableTo(resourceTypeRole),
+			ent.WithDescription(fmt.Sprintf("%s privilege of the %s role in zoom", privilege, resource.DisplayName)),
+			ent.WithDisplayName(fmt.Sprintf("%s role %s", resource.DisplayName, privilege)),
+			ent.WithAnnotation(&v2.EntitlementImmutable{}),
+		}
+		rv = append(rv, ent.NewPermissionEntitlement(resource, privilege, privilegeOptions...))
+	}
+
 	return rv, "", nil, nil
 }
 
+// rolePrivileges reads the privileges stored in the profile of a role resource.
+func rolePrivileges(role *v2.Resource) ([]string, error) {
+	trait, err := resource.GetRoleTrait(role)
+	if err != nil {
+		return nil, err
+	}
+
+	var privileges []string
+	for _, v := range trait.GetProfile().GetFields()["privileges"].GetListValue().GetValues() {
+		privileges = append(privileges, v.GetStringValue())
+	}
+
+	return privileges, nil
+}
+
+// roleTypeOf reads the role type stored in the profile of a role resource.
+func roleTypeOf(role *v2.Resource) (string, error) {
+	trait, err := resource.GetRoleTrait(role)
+	if err != nil {
+		return "", err
+	}
+
+	return trait.GetProfile().GetFields()["role_type"].GetStringValue(), nil
+}
+
 func (r *roleResourceType) Grants(ctx context.Context, resource *v2.Resource, token *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
 	var rv []*v2.Grant
 	var pageToken string
@@ -104,25 +222,24 @@ func (r *roleResourceType) Grants(ctx context.Context, resource *v2.Resource, to
 		return nil, "", nil, err
 	}
 
-	roleMembers, nextToken, resp, err := r.client.GetRoleMembers(ctx, resource.Id.Resource, page)
+	roleMembers, err := r.client.GetRoleMembers(ctx, resource.Id.Resource, page)
 	if err != nil {
 		return nil, "", nil, err
 	}
-	resp.Body.Close()
 
-	if nextToken != "" {
-		pageToken, err = bag.NextToken(nextToken)
+	if roleMembers.NextPageToken != "" {
+		pageToken, err = bag.NextToken(roleMembers.NextPageToken)
 		if err != nil {
 			return nil, "", nil, err
 		}
 	}
 
-	annos, err := parseResp(resp)
+	annos, err := parseResp(roleMembers.Response)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	for _, member := range roleMembers {
+	for _, member := range roleMembers.Items {
 		memberCopy := member
 		ur, err := userResource(memberCopy, resource.Id)
 		if err != nil {
@@ -133,10 +250,25 @@ func (r *roleResourceType) Grants(ctx context.Context, resource *v2.Resource, to
 		rv = append(rv, grant)
 	}
 
+	// The role holds its privileges, members get them through expansion of the member entitlement.
+	if page == "" {
+		privileges, err := rolePrivileges(resource)
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		memberEntitlementId := ent.NewEntitlementID(resource, memberEntitlement)
+		for _, privilege := range privileges {
+			rv = append(rv, grant.NewGrant(resource, privilege, resource.Id, grant.WithAnnotation(&v2.GrantExpandable{
+				EntitlementIds: []string{memberEntitlementId},
+			})))
+		}
+	}
+
 	return rv, pageToken, annos, nil
 }
 
-func (r *roleResourceType) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+func (r *roleResourceType) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) ([]*v2.Grant, annotations.Annotations, error) {
 	l := ctxzap.Extract(ctx)
 
 	if principal.Id.ResourceType != resourceTypeUser.Id {
@@ -145,15 +277,96 @@ func (r *roleResourceType) Grant(ctx context.Context, principal *v2.Resource, en
 			zap.String("principal_type", principal.Id.ResourceType),
 			zap.String("principal_id", principal.Id.Resource),
 		)
-		return nil, fmt.Errorf("baton-zoom: only users can be granted role membership")
+		return nil, nil, fmt.Errorf("baton-zoom: only users can be granted role membership")
 	}
 
-	err := r.client.AssignRole(ctx, entitlement.Resource.Id.Resource, principal.Id.Resource)
+	if entitlement.Slug != memberEntitlement {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "baton-zoom: privilege %s comes with the role, grant the role instead", entitlement.Slug)
+	}
+
+	roleId := entitlement.Resource.Id.Resource
+	userId := principal.Id.Resource
+
+	role, err := r.client.GetRole(ctx, roleId)
 	if err != nil {
-		return nil, fmt.Errorf("baton-zoom: failed to assign role to user: %w", err)
+		return nil, nil, fmt.Errorf("baton-zoom: failed to get role %s: %w", roleId, err)
 	}
 
-	return nil, nil
+	// A Zoom user has exactly one common role, assigning a new one silently replaces the previous one.
+	var user zoom.User
+	if isExclusiveRole(role) {
+		err = r.protected.check(ctx, userId, "replace role")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		user, _, err = r.client.GetUser(ctx, userId)
+		if err != nil {
+			return nil, nil, fmt.Errorf("baton-zoom: failed to get user %s: %w", userId, err)
+		}
+	}
+
+	var annos annotations.Annotations
+	err = r.client.AssignRole(ctx, roleId, userId)
+	if err != nil {
+		if !zoom.IsAlreadyMember(err) {
+			return nil, nil, fmt.Errorf("baton-zoom: failed to assign role to user: %w", err)
+		}
+		l.Info(
+			"baton-zoom: user already has the role",
+			zap.String("role_id", roleId),
+			zap.String("user_id", userId),
+		)
+		annos.Update(&v2.GrantAlreadyExists{})
+	}
+
+	if r.client.DryRun() {
+		annos = withMarkers(annos, dryRunMarker)
+	} else {
+		found, err := r.hasMember(ctx, role, userId)
+		if err != nil {
+			return nil, nil, fmt.Errorf("baton-zoom: failed to confirm role assignment: %w", err)
+		}
+		if !found {
+			return nil, nil, fmt.Errorf("baton-zoom: user %s does not have role %s after the grant", userId, roleId)
+		}
+	}
+
+	var grantOptions []grant.GrantOption
+	if user.RoleID != "" && user.RoleID != roleId {
+		l.Info(
+			"baton-zoom: role assignment replaced the user's previous role",
+			zap.String("user_id", userId),
+			zap.String("role_id", roleId),
+			zap.String("previous_role_id", user.RoleID),
+			zap.String("previous_role_name", user.RoleName),
+		)
+		grantOptions = append(grantOptions, grant.WithGrantMetadata(map[string]interface{}{
+			"previous_role_id":   user.RoleID,
+			"previous_role_name": user.RoleName,
+		}))
+	}
+
+	return []*v2.Grant{grant.NewGrant(entitlement.Resource, memberEntitlement, principal.Id, grantOptions...)}, annos, nil
+}
+
+// hasMember reports whether the user holds the role. A common role is the user's role ID, only IQ and phone roles
+// need a walk of their members.
+func (r *roleResourceType) hasMember(ctx context.Context, role *zoom.Role, userId string) (bool, error) {
+	roleId := role.ID
+	if isExclusiveRole(role) {
+		user, _, err := r.client.GetUser(ctx, userId)
+		if err != nil {
+			return false, err
+		}
+		return user.RoleID == roleId, nil
+	}
+
+	_, found, err := findPaged(ctx, r.roleMembers(roleId), func(u zoom.User) bool {
+		return u.ID == userId
+	})
+
+	return found, err
 }
 
 func (r *roleResourceType) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
@@ -171,17 +384,385 @@ func (r *roleResourceType) Revoke(ctx context.Context, grant *v2.Grant) (annotat
 		return nil, fmt.Errorf("baton-zoom: only users can have role membership revoked")
 	}
 
-	err := r.client.UnassignRole(ctx, entitlement.Resource.Id.Resource, principal.Id.Resource)
+	if entitlement.Slug != memberEntitlement {
+		return nil, status.Errorf(codes.InvalidArgument, "baton-zoom: privilege %s comes with the role, revoke the role instead", entitlement.Slug)
+	}
+
+	roleId := entitlement.Resource.Id.Resource
+	userId := principal.Id.Resource
+
+	err := r.protected.check(ctx, userId, "revoke role")
+	if err != nil {
+		return nil, err
+	}
+
+	annos, err := r.checkLastAdmin(ctx, roleId, userId)
+	if err != nil || annos.Contains(&v2.GrantAlreadyRevoked{}) {
+		return annos, err
+	}
+
+	role, err := r.client.GetRole(ctx, roleId)
+	if err != nil {
+		return nil, fmt.Errorf("baton-zoom: failed to get role %s: %w", roleId, err)
+	}
+
+	// IQ and phone roles are held next to the common role, revoking one doesn't move the user anywhere.
+	if !isExclusiveRole(role) {
+		return r.unassign(ctx, roleId, userId)
+	}
+
+	defaultRoleId, err := r.resolveDefaultRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if roleId == defaultRoleId {
+		return nil, status.Errorf(
+			codes.FailedPrecondition,
+			"baton-zoom: every Zoom user has a role, grant another role instead of revoking the default role %s",
+			r.defaultRole,
+		)
+	}
+
+	// The user may have been moved to another role or removed since the last sync, which already revoked this one.
+	user, _, err := r.client.GetUser(ctx, userId)
 	if err != nil {
+		if zoom.IsUserNotFound(err) {
+			l.Info(
+				"baton-zoom: user no longer exists",
+				zap.String("role_id", roleId),
+				zap.String("user_id", userId),
+			)
+			return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+		}
+		return nil, fmt.Errorf("baton-zoom: failed to get user %s: %w", userId, err)
+	}
+	if user.RoleID != roleId {
+		l.Info(
+			"baton-zoom: user no longer has the role",
+			zap.String("role_id", roleId),
+			zap.String("user_id", userId),
+			zap.String("current_role_id", user.RoleID),
+		)
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+
+	annos, err = r.unassign(ctx, roleId, userId)
+	if err != nil || annos.Contains(&v2.GrantAlreadyRevoked{}) {
+		return annos, err
+	}
+	if r.client.DryRun() {
+		return annos, nil
+	}
+
+	// Zoom moves unassigned users to Member, move them on if another default role is configured.
+	user, _, err = r.client.GetUser(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("baton-zoom: failed to get user %s: %w", userId, err)
+	}
+	if user.RoleID != defaultRoleId {
+		err = r.client.AssignRole(ctx, defaultRoleId, userId)
+		if err != nil {
+			return nil, fmt.Errorf("baton-zoom: failed to move user to the default role %s: %w", r.defaultRole, err)
+		}
+	}
+
+	l.Info(
+		"baton-zoom: revoked role, user moved to the default role",
+		zap.String("role_id", roleId),
+		zap.String("user_id", userId),
+		zap.String("default_role_id", defaultRoleId),
+	)
+
+	return nil, nil
+}
+
+// checkLastAdmin refuses to revoke the Admin role from its last member, which would leave only the owner to manage the account.
+// When the single member left isn't userId, the user already lost the role and the revoke is reported as done.
+func (r *roleResourceType) checkLastAdmin(ctx context.Context, roleId string, userId string) (annotations.Annotations, error) {
+	if roleId != zoom.AdminRoleID {
+		return nil, nil
+	}
+
+	members, err := r.client.GetRoleMembers(ctx, roleId, "")
+	if err != nil {
+		return nil, fmt.Errorf("baton-zoom: failed to list members of role %s: %w", roleId, err)
+	}
+	if len(members.Items) > 1 || members.NextPageToken != "" {
+		return nil, nil
+	}
+	if len(members.Items) == 0 || members.Items[0].ID != userId {
+		ctxzap.Extract(ctx).Info(
+			"baton-zoom: user no longer has the role",
+			zap.String("role_id", roleId),
+			zap.String("user_id", userId),
+		)
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+
+	ctxzap.Extract(ctx).Warn(
+		"baton-zoom: refused to revoke the last Admin",
+		zap.String("role_id", roleId),
+		zap.String("user_id", userId),
+	)
+
+	return nil, status.Error(codes.PermissionDenied, "baton-zoom: refusing to revoke the Admin role from its last member")
+}
+
+func (r *roleResourceType) unassign(ctx context.Context, roleId string, userId string) (annotations.Annotations, error) {
+	err := r.client.UnassignRole(ctx, roleId, userId)
+	if err != nil {
+		if zoom.IsNotMember(err) || zoom.IsUserNotFound(err) {
+			ctxzap.Extract(ctx).Info(
+				"baton-zoom: user no longer has the role",
+				zap.String("role_id", roleId),
+				zap.String("user_id", userId),
+			)
+			return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+		}
 		return nil, fmt.Errorf("baton-zoom: failed to unassign role from user: %w", err)
 	}
 
+	if r.client.DryRun() {
+		return withMarkers(nil, dryRunMarker), nil
+	}
+
+	return nil, nil
+}
+
+// Create makes a custom role from the name, description and the privileges and role_type of the role profile.
+// A role with the same name is never changed, Create fails with AlreadyExists instead.
+func (r *roleResourceType) Create(ctx context.Context, role *v2.Resource) (*v2.Resource, annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	if role.DisplayName == "" {
+		return nil, nil, status.Error(codes.InvalidArgument, "baton-zoom: a role needs a name")
+	}
+
+	trait, err := resource.GetRoleTrait(role)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "baton-zoom: role is missing its role trait: %v", err)
+	}
+	privileges, err := rolePrivileges(role)
+	if err != nil {
+		return nil, nil, err
+	}
+	roleType := trait.GetProfile().GetFields()["role_type"].GetStringValue()
+	if roleType == "" {
+		roleType = zoom.RoleTypeCommon
+	}
+	if !slices.Contains(zoom.RoleTypes, roleType) {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "baton-zoom: unknown role type %s", roleType)
+	}
+
+	body := &zoom.RoleBody{
+		Name:        role.DisplayName,
+		Description: role.Description,
+		Privileges:  privileges,
+	}
+
+	// Changing an existing role would change the access of everyone holding it, so taken names are refused.
+	existing, err := r.findRoleByName(ctx, roleType, role.DisplayName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if existing != nil {
+		return nil, nil, status.Errorf(codes.AlreadyExists, "baton-zoom: a %s role named %s already exists as %s", roleType, existing.Name, existing.ID)
+	}
+
+	// The type can only be set when the role is created.
+	body.Type = roleType
+	created, err := r.client.CreateRole(ctx, body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-zoom: failed to create role %s: %w", role.DisplayName, err)
+	}
+
+	// Nothing comes back from a dry run, the requested role is the best answer.
+	if r.client.DryRun() {
+		return role, withMarkers(nil, dryRunMarker), nil
+	}
+	l.Info(
+		"baton-zoom: created custom role",
+		zap.String("role_id", created.ID),
+		zap.String("role_name", created.Name),
+	)
+
+	if created.Type == "" {
+		created.Type = roleType
+	}
+	if created.Privileges == nil {
+		created.Privileges = privileges
+	}
+
+	rr, err := roleResource(*created, role.ParentResourceId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rr, nil, nil
+}
+
+// findRoleByName looks up a role of the given type by name, it returns nil when there is none.
+func (r *roleResourceType) findRoleByName(ctx context.Context, roleType string, name string) (*zoom.Role, error) {
+	role, ok, err := findPaged(ctx, r.roles(roleType), func(role zoom.Role) bool {
+		return strings.EqualFold(role.Name, name)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("baton-zoom: failed to list roles: %w", err)
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	return &role, nil
+}
+
+// Delete removes a custom role. Built-in roles and the default role are never deleted, roles that
+// still have members are only deleted when forced, after moving their members out.
+func (r *roleResourceType) Delete(ctx context.Context, resourceId *v2.ResourceId) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	roleId := resourceId.Resource
+
+	if zoom.IsBuiltInRole(roleId) {
+		return nil, status.Errorf(codes.FailedPrecondition, "baton-zoom: role %s is a built-in role and can't be deleted", roleId)
+	}
+
+	role, err := r.client.GetRole(ctx, roleId)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			l.Info("baton-zoom: role already deleted", zap.String("role_id", roleId))
+			return nil, nil
+		}
+		return nil, fmt.Errorf("baton-zoom: failed to get role %s: %w", roleId, err)
+	}
+
+	members, err := collectPaged(ctx, r.roleMembers(roleId))
+	if err != nil {
+		return nil, fmt.Errorf("baton-zoom: failed to list members of role %s: %w", roleId, err)
+	}
+
+	if len(members) > 0 {
+		if !r.forceDelete {
+			return nil, status.Errorf(
+				codes.FailedPrecondition,
+				"baton-zoom: role %s still has %d members, revoke them first or force the deletion",
+				role.Name,
+				len(members),
+			)
+		}
+
+		err = r.removeMembers(ctx, role, members)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = r.client.DeleteRole(ctx, roleId)
+	if err != nil {
+		return nil, fmt.Errorf("baton-zoom: failed to delete role %s: %w", roleId, err)
+	}
+
+	if r.client.DryRun() {
+		return withMarkers(nil, dryRunMarker), nil
+	}
+
+	l.Info(
+		"baton-zoom: deleted custom role",
+		zap.String("role_id", roleId),
+		zap.String("role_name", role.Name),
+		zap.Int("moved_members", len(members)),
+	)
+
 	return nil, nil
 }
 
-func roleBuilder(client *zoom.Client) *roleResourceType {
+// removeMembers takes a role away from its members before a forced deletion. Members of a common role
+// are moved to the default role, members of IQ and phone roles just lose the role. Nobody is touched
+// when any member is protected.
+func (r *roleResourceType) removeMembers(ctx context.Context, role *zoom.Role, members []zoom.User) error {
+	for _, member := range members {
+		err := r.protected.check(ctx, member.ID, fmt.Sprintf("delete role %s", role.Name))
+		if err != nil {
+			return err
+		}
+	}
+
+	if !isExclusiveRole(role) {
+		for _, member := range members {
+			if _, err := r.unassign(ctx, role.ID, member.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	defaultRoleId, err := r.resolveDefaultRole(ctx)
+	if err != nil {
+		return err
+	}
+	if role.ID == defaultRoleId {
+		return status.Errorf(codes.FailedPrecondition, "baton-zoom: role %s is the default role and can't be deleted", role.Name)
+	}
+
+	// Assigning another common role replaces this one.
+	for _, member := range members {
+		err = r.client.AssignRole(ctx, defaultRoleId, member.ID)
+		if err != nil && !zoom.IsAlreadyMember(err) {
+			return fmt.Errorf("baton-zoom: failed to move user %s to the default role %s: %w", member.ID, r.defaultRole, err)
+		}
+	}
+
+	return nil
+}
+
+// isExclusiveRole reports whether the role is a common role, of which a user has exactly one.
+func isExclusiveRole(role *zoom.Role) bool {
+	return role.Type == "" || role.Type == zoom.RoleTypeCommon
+}
+
+// resolveDefaultRole looks up the ID of the configured default role, which may be given by name or ID.
+func (r *roleResourceType) resolveDefaultRole(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.defaultRoleId != "" {
+		return r.defaultRoleId, nil
+	}
+
+	role, ok, err := findPaged(ctx, r.roles(zoom.RoleTypeCommon), func(role zoom.Role) bool {
+		return role.ID == r.defaultRole || strings.EqualFold(role.Name, r.defaultRole)
+	})
+	if err != nil {
+		return "", fmt.Errorf("baton-zoom: failed to list roles: %w", err)
+	}
+	if !ok {
+		return "", fmt.Errorf("baton-zoom: default role %s not found", r.defaultRole)
+	}
+	r.defaultRoleId = role.ID
+
+	return role.ID, nil
+}
+
+// roles lists the roles of a type page by page, for findPaged and collectPaged.
+func (r *roleResourceType) roles(roleType string) func(ctx context.Context, nextToken string) (*zoom.Page[zoom.Role], error) {
+	return func(ctx context.Context, nextToken string) (*zoom.Page[zoom.Role], error) {
+		return r.client.GetRoles(ctx, roleType, nextToken)
+	}
+}
+
+// roleMembers lists the members of a role page by page, for findPaged and collectPaged.
+func (r *roleResourceType) roleMembers(roleId string) func(ctx context.Context, nextToken string) (*zoom.Page[zoom.User], error) {
+	return func(ctx context.Context, nextToken string) (*zoom.Page[zoom.User], error) {
+		return r.client.GetRoleMembers(ctx, roleId, nextToken)
+	}
+}
+
+func roleBuilder(client roleAPI, protected *protectedPrincipals, defaultRole string, forceDelete bool) *roleResourceType {
 	return &roleResourceType{
 		resourceType: resourceTypeRole,
 		client:       client,
+		protected:    protected,
+		defaultRole:  defaultRole,
+		forceDelete:  forceDelete,
 	}
 }
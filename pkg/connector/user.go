@@ -3,6 +3,9 @@ package connector
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
@@ -10,13 +13,23 @@ import (
 	"github.com/conductorone/baton-sdk/pkg/pagination"
 	"github.com/conductorone/baton-sdk/pkg/types/resource"
 	"github.com/conductorone/baton-zoom/pkg/zoom"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 type userResourceType struct {
 	resourceType *v2.ResourceType
-	client       *zoom.Client
+	client       userAPI
+	protected    *protectedPrincipals
+	// statuses are the user statuses synced, see zoom.UserStatuses.
+	statuses    []string
+	deprovision deprovisioning
+	// resendInvites makes CreateAccount invite users again that already exist but are still pending.
+	resendInvites bool
+	// details makes List get every user on its own for the fields Zoom leaves out of listings, one request per user.
+	details bool
 }
 
 func (u *userResourceType) ResourceType(_ context.Context) *v2.ResourceType {
@@ -32,6 +45,37 @@ func userResource(user zoom.User, parentResourceID *v2.ResourceId) (*v2.Resource
 		"user_id":    user.ID,
 	}
 
+	details := map[string]string{
+		"department":          user.Department,
+		"job_title":           user.JobTitle,
+		"manager":             user.Manager,
+		"location":            user.Location,
+		"timezone":            user.Timezone,
+		"created_at":          user.CreatedAt,
+		"last_login_time":     user.LastLoginTime,
+		"last_client_version": user.LastClientVersion,
+		"employee_unique_id":  user.EmployeeUniqueID,
+	}
+	for k, v := range details {
+		if v != "" {
+			profile[k] = v
+		}
+	}
+	if user.PMI != 0 {
+		profile["pmi"] = user.PMI
+	}
+	profile["verified"] = user.Verified == 1
+	if len(user.LoginTypes) > 0 {
+		loginTypes := make([]interface{}, 0, len(user.LoginTypes))
+		for _, loginType := range user.LoginTypes {
+			loginTypes = append(loginTypes, loginType)
+		}
+		profile["login_types"] = loginTypes
+	}
+	for _, attribute := range user.CustomAttributes {
+		profile[customAttributeKey(attribute)] = attribute.Value
+	}
+
 	var userStatus v2.UserTrait_Status_Status
 
 	switch user.Status {
@@ -51,6 +95,16 @@ func userResource(user zoom.User, parentResourceID *v2.ResourceId) (*v2.Resource
 		resource.WithEmail(user.Email, true),
 	}
 
+	if createdAt, ok := parseTime(user.CreatedAt); ok {
+		userTraitTraitOptions = append(userTraitTraitOptions, resource.WithCreatedAt(createdAt))
+	}
+	if lastLogin, ok := parseTime(user.LastLoginTime); ok {
+		userTraitTraitOptions = append(userTraitTraitOptions, resource.WithLastLogin(lastLogin))
+	}
+	if user.EmployeeUniqueID != "" {
+		userTraitTraitOptions = append(userTraitTraitOptions, resource.WithEmployeeID(user.EmployeeUniqueID))
+	}
+
 	ret, err := resource.NewUserResource(
 		user.DisplayName,
 		resourceTypeUser,
@@ -65,36 +119,91 @@ func userResource(user zoom.User, parentResourceID *v2.ResourceId) (*v2.Resource
 	return ret, nil
 }
 
-func (u *userResourceType) List(ctx context.Context, parentId *v2.ResourceId, token *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
-	var pageToken string
-	var rv []*v2.Resource
+// customAttributeKey names the profile field of a custom attribute, e.g. "custom_attribute_cost_center".
+func customAttributeKey(attribute zoom.CustomAttribute) string {
+	name := attribute.Name
+	if name == "" {
+		name = attribute.Key
+	}
+
+	return "custom_attribute_" + strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")
+}
+
+// parseTime reads a Zoom timestamp, which is RFC 3339 in UTC.
+func parseTime(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
 
-	bag, page, err := parsePageToken(token.Token, &v2.ResourceId{ResourceType: resourceTypeGroup.Id})
+	t, err := time.Parse(time.RFC3339, value)
 	if err != nil {
-		return nil, "", nil, err
+		return time.Time{}, false
 	}
 
-	users, nextPage, resp, err := u.client.GetUsers(ctx, page)
+	return t, true
+}
+
+// listUsersPage lists the next page of users of the given statuses. Zoom lists users of each status separately,
+// the bag holds one page state per status still to list. No page is returned when there are no statuses.
+func listUsersPage(ctx context.Context, client userLister, statuses []string, token string) (*zoom.Page[zoom.User], string, error) {
+	bag := &pagination.Bag{}
+	err := bag.Unmarshal(token)
 	if err != nil {
-		return nil, "", nil, err
+		return nil, "", err
 	}
-	resp.Body.Close()
 
-	if nextPage != "" {
-		pageToken, err = bag.NextToken(nextPage)
-		if err != nil {
-			return nil, "", nil, err
+	if bag.Current() == nil {
+		if len(statuses) == 0 {
+			return nil, "", nil
+		}
+		for i := len(statuses) - 1; i >= 0; i-- {
+			bag.Push(pagination.PageState{
+				ResourceTypeID: resourceTypeUser.Id,
+				ResourceID:     statuses[i],
+			})
 		}
 	}
 
-	annos, err := parseResp(resp)
+	users, err := client.GetUsers(ctx, bag.ResourceID(), bag.PageToken())
+	if err != nil {
+		return nil, "", err
+	}
+
+	err = bag.Next(users.NextPageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	pageToken, err := bag.Marshal()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return users, pageToken, nil
+}
+
+func (u *userResourceType) List(ctx context.Context, parentId *v2.ResourceId, token *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var rv []*v2.Resource
+
+	users, pageToken, err := listUsersPage(ctx, u.client, u.statuses, token.Token)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if users == nil {
+		return nil, "", nil, nil
+	}
+
+	annos, err := parseResp(users.Response)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	for _, user := range users {
-		userCopy := user
-		ur, err := userResource(userCopy, parentId)
+	for _, user := range users.Items {
+		if u.details {
+			user = u.userDetails(ctx, user)
+		}
+
+		ur, err := userResource(user, parentId)
 		if err != nil {
 			return nil, "", nil, err
 		}
@@ -104,6 +213,23 @@ func (u *userResourceType) List(ctx context.Context, parentId *v2.ResourceId, to
 	return rv, pageToken, annos, nil
 }
 
+// userDetails adds the job title, manager, location and login types, which Zoom only returns for a single user, to a
+// listed user. A user that can't be got, e.g. deleted since the page was listed or pending, keeps the listed fields
+// rather than failing the whole page.
+func (u *userResourceType) userDetails(ctx context.Context, user zoom.User) zoom.User {
+	details, _, err := u.client.GetUser(ctx, user.ID)
+	if err != nil {
+		ctxzap.Extract(ctx).Warn(
+			"baton-zoom: failed to get user details, syncing the listed fields only",
+			zap.String("user_id", user.ID),
+			zap.Error(err),
+		)
+		return user
+	}
+
+	return details
+}
+
 func (u *userResourceType) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
 	return nil, "", nil, nil
 }
@@ -131,22 +257,82 @@ func (u *userResourceType) CreateAccount(
 		return nil, nil, nil, err
 	}
 
+	// Resolve the group and role up front, a typo shouldn't leave a half set up user behind.
+	placement, err := u.resolvePlacement(ctx, accountInfo)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	newUser, err := u.client.CreateUser(ctx, newUserInfo)
+	if zoom.IsUserAlreadyExists(err) {
+		existing, _, getErr := u.client.GetUser(ctx, newUserInfo.UserInfo.Email)
+		if getErr != nil {
+			return nil, nil, nil, fmt.Errorf("baton-zoom: failed to get existing user %s: %w", newUserInfo.UserInfo.Email, getErr)
+		}
+		if existing.Status != zoom.UserStatusPending || !u.resendInvites {
+			return u.adopt(ctx, existing, placement)
+		}
+
+		existing, cancelled, cancelErr := u.cancelInvitation(ctx, existing.ID)
+		if cancelErr != nil {
+			return nil, nil, nil, cancelErr
+		}
+		if !cancelled {
+			return u.adopt(ctx, existing, placement)
+		}
+		newUser, err = u.client.CreateUser(ctx, newUserInfo)
+	}
 	if err != nil {
+		if zoom.IsOutOfLicenses(err) {
+			return nil, nil, nil, status.Errorf(codes.ResourceExhausted, "baton-zoom: the account has no licenses left for user %s: %v", newUserInfo.UserInfo.Email, err)
+		}
 		return nil, nil, nil, err
 	}
 
+	// No user was created, so there is no ID to report. The email stands in for it.
+	if u.client.DryRun() {
+		u.place(ctx, newUserInfo.UserInfo.Email, placement)
+
+		userResource, err := userResource(zoom.User{
+			ID:          newUserInfo.UserInfo.Email,
+			FirstName:   newUserInfo.UserInfo.FirstName,
+			LastName:    newUserInfo.UserInfo.LastName,
+			DisplayName: newUserInfo.UserInfo.DisplayName,
+			Email:       newUserInfo.UserInfo.Email,
+		}, nil)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		return &v2.CreateAccountResponse_ActionRequiredResult{
+			Resource: userResource,
+			Message:  "dry run: the user was not created",
+		}, nil, withMarkers(nil, dryRunMarker), nil
+	}
+
+	failures := u.place(ctx, newUser.Id, placement)
+
 	userResource, err := userResource(zoom.User{
-		ID:        newUser.Id,
-		FirstName: newUser.FirstName,
-		LastName:  newUser.LastName,
-		Email:     newUser.Email,
-		Type:      newUser.Type,
+		ID:         newUser.Id,
+		FirstName:  newUser.FirstName,
+		LastName:   newUser.LastName,
+		Email:      newUser.Email,
+		Type:       newUser.Type,
+		Department: placement.department,
+		JobTitle:   placement.jobTitle,
 	}, nil)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
+	// The user exists either way, failing would only make the caller retry the creation.
+	if len(failures) > 0 {
+		return &v2.CreateAccountResponse_ActionRequiredResult{
+			Resource: userResource,
+			Message:  fmt.Sprintf("user created, but %s", strings.Join(failures, "; ")),
+		}, nil, nil, nil
+	}
+
 	caResponse := &v2.CreateAccountResponse_SuccessResult{
 		Resource: userResource,
 	}
@@ -154,6 +340,97 @@ func (u *userResourceType) CreateAccount(
 	return caResponse, nil, nil, nil
 }
 
+// adopt reports a user that was already in the account as created, so retried creations succeed. The
+// placement is applied to them like to a new user, deactivated users are left alone and need action.
+func (u *userResourceType) adopt(ctx context.Context, existing zoom.User, placement accountPlacement) (connectorbuilder.CreateAccountResponse, []*v2.PlaintextData, annotations.Annotations, error) {
+	ctxzap.Extract(ctx).Info(
+		"baton-zoom: user already exists, adopting them",
+		zap.String("user_id", existing.ID),
+		zap.String("status", existing.Status),
+	)
+
+	annos := withMarkers(nil, alreadyExistsMarker)
+
+	if existing.Status == zoom.UserStatusInactive {
+		userResource, err := userResource(existing, nil)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		return &v2.CreateAccountResponse_ActionRequiredResult{
+			Resource: userResource,
+			Message:  "user already exists but is deactivated, enable them with the enable_user action",
+		}, nil, annos, nil
+	}
+
+	failures := u.place(ctx, existing.ID, placement)
+	if placement.department != "" {
+		existing.Department = placement.department
+	}
+	if placement.jobTitle != "" {
+		existing.JobTitle = placement.jobTitle
+	}
+
+	userResource, err := userResource(existing, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if len(failures) > 0 {
+		return &v2.CreateAccountResponse_ActionRequiredResult{
+			Resource: userResource,
+			Message:  fmt.Sprintf("user already exists, but %s", strings.Join(failures, "; ")),
+		}, nil, annos, nil
+	}
+
+	return &v2.CreateAccountResponse_SuccessResult{
+		Resource: userResource,
+	}, nil, annos, nil
+}
+
+// cancelInvitation deletes a pending user so they can be invited again, Zoom has no endpoint to re-send an
+// invitation. Protected users are refused, and the user is only deleted if they are still pending, otherwise
+// cancelled is false and user is their current state.
+func (u *userResourceType) cancelInvitation(ctx context.Context, userId string) (zoom.User, bool, error) {
+	err := u.protected.check(ctx, userId, "re-invite user")
+	if err != nil {
+		return zoom.User{}, false, err
+	}
+
+	user, _, err := u.client.GetUser(ctx, userId)
+	if err != nil {
+		return zoom.User{}, false, fmt.Errorf("baton-zoom: failed to get user %s: %w", userId, err)
+	}
+	if user.Status != zoom.UserStatusPending {
+		return user, false, nil
+	}
+
+	err = u.client.DeleteUser(ctx, userId, zoom.DeleteUserOptions{Action: zoom.DeleteUser})
+	if err != nil {
+		return zoom.User{}, false, fmt.Errorf("baton-zoom: failed to cancel the invitation of user %s: %w", userId, err)
+	}
+
+	return user, true, nil
+}
+
+// Optional account creation fields, see Metadata.
+const (
+	createActionField = "create_action"
+	userTypeField     = "user_type"
+	departmentField   = "department"
+	jobTitleField     = "job_title"
+	initialGroupField = "initial_group"
+	initialRoleField  = "initial_role"
+)
+
+var createActions = []zoom.ActionType{zoom.CreateUser, zoom.AutoCreateUser, zoom.CustCreateUser, zoom.SSOCreate}
+
+// createUserTypes are the user types accepted on account creation, named like the licenses.
+var createUserTypes = map[string]zoom.UserType{
+	"basic":    zoom.BasicUser,
+	"licensed": zoom.LicensedUser,
+}
+
 func createNewUserInfo(accountInfo *v2.AccountInfo) (*zoom.UserCreationBody, error) {
 	pMap := accountInfo.Profile.AsMap()
 
@@ -177,10 +454,26 @@ func createNewUserInfo(accountInfo *v2.AccountInfo) (*zoom.UserCreationBody, err
 		return nil, fmt.Errorf("display name is required")
 	}
 
+	action := zoom.CreateUser
+	if v := profileString(pMap, createActionField); v != "" {
+		action = zoom.ActionType(v)
+		if !slices.Contains(createActions, action) {
+			return nil, fmt.Errorf("unknown create action %s", v)
+		}
+	}
+
+	userType := zoom.BasicUser
+	if v := profileString(pMap, userTypeField); v != "" {
+		userType, ok = createUserTypes[strings.ToLower(v)]
+		if !ok {
+			return nil, fmt.Errorf("unknown user type %s", v)
+		}
+	}
+
 	newUserInfo := &zoom.UserCreationBody{
-		Action: zoom.CreateUser,
+		Action: action,
 		UserInfo: zoom.UserCreationInfo{
-			Type:        zoom.BasicUser,
+			Type:        userType,
 			FirstName:   firstName,
 			LastName:    lastName,
 			Email:       email,
@@ -191,26 +484,140 @@ func createNewUserInfo(accountInfo *v2.AccountInfo) (*zoom.UserCreationBody, err
 	return newUserInfo, nil
 }
 
+func profileString(pMap map[string]interface{}, key string) string {
+	v, _ := pMap[key].(string)
+	return strings.TrimSpace(v)
+}
+
+// accountPlacement is what CreateAccount sets up for a user once Zoom created them.
+type accountPlacement struct {
+	department string
+	jobTitle   string
+	group      *zoom.Group
+	role       *zoom.Role
+}
+
+// resolvePlacement reads the optional placement fields of the profile, looking up the group and role by ID or name.
+func (u *userResourceType) resolvePlacement(ctx context.Context, accountInfo *v2.AccountInfo) (accountPlacement, error) {
+	pMap := accountInfo.Profile.AsMap()
+	placement := accountPlacement{
+		department: profileString(pMap, departmentField),
+		jobTitle:   profileString(pMap, jobTitleField),
+	}
+
+	if ref := profileString(pMap, initialGroupField); ref != "" {
+		group, ok, err := findPaged(ctx, u.client.GetGroups, func(g zoom.Group) bool {
+			return g.ID == ref || strings.EqualFold(g.Name, ref)
+		})
+		if err != nil {
+			return placement, fmt.Errorf("baton-zoom: failed to list groups: %w", err)
+		}
+		if !ok {
+			return placement, status.Errorf(codes.InvalidArgument, "baton-zoom: group %s not found", ref)
+		}
+		placement.group = &group
+	}
+
+	if ref := profileString(pMap, initialRoleField); ref != "" {
+		listRoles := func(ctx context.Context, nextToken string) (*zoom.Page[zoom.Role], error) {
+			return u.client.GetRoles(ctx, zoom.RoleTypeCommon, nextToken)
+		}
+		role, ok, err := findPaged(ctx, listRoles, func(r zoom.Role) bool {
+			return r.ID == ref || strings.EqualFold(r.Name, ref)
+		})
+		if err != nil {
+			return placement, fmt.Errorf("baton-zoom: failed to list roles: %w", err)
+		}
+		if !ok {
+			return placement, status.Errorf(codes.InvalidArgument, "baton-zoom: role %s not found", ref)
+		}
+		placement.role = &role
+	}
+
+	return placement, nil
+}
+
+// place applies the placement to a new user. The user exists by now, so failures are collected rather than returned.
+func (u *userResourceType) place(ctx context.Context, userId string, placement accountPlacement) []string {
+	var failures []string
+
+	if placement.department != "" || placement.jobTitle != "" {
+		err := u.client.UpdateUser(ctx, userId, &zoom.UserUpdateBody{Dept: placement.department, JobTitle: placement.jobTitle})
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("setting the department and job title failed: %v", err))
+		}
+	}
+
+	if placement.group != nil {
+		err := u.client.AddGroupMembers(ctx, placement.group.ID, userId)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("adding them to group %s failed: %v", placement.group.Name, err))
+		}
+	}
+
+	if placement.role != nil && placement.role.ID != zoom.MemberRoleID {
+		err := u.client.AssignRole(ctx, placement.role.ID, userId)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("assigning role %s failed: %v", placement.role.Name, err))
+		}
+	}
+
+	for _, failure := range failures {
+		ctxzap.Extract(ctx).Warn(
+			"baton-zoom: failed to set up new user",
+			zap.String("user_id", userId),
+			zap.String("failure", failure),
+		)
+	}
+
+	return failures
+}
+
+// Delete deprovisions the user the configured way, see WithDeprovisionMode.
 func (u *userResourceType) Delete(ctx context.Context, principal *v2.ResourceId) (annotations.Annotations, error) {
 	userID := principal.Resource
 
-	err := u.client.DeleteUser(ctx, userID)
+	err := u.protected.check(ctx, userID, "delete user")
+	if err != nil {
+		return nil, err
+	}
+
+	user, _, err := u.client.GetUser(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	_, resp, err := u.client.GetUser(ctx, userID)
-	if err == nil || status.Code(err) != codes.NotFound {
-		return nil, fmt.Errorf("error deleting user. User %s still exists", userID)
+	if u.deprovision.mode == DeprovisionDeactivate {
+		changed, err := u.deactivate(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		if changed && u.client.DryRun() {
+			return withMarkers(nil, dryRunMarker), nil
+		}
+
+		return nil, nil
+	}
+
+	err = u.remove(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if u.client.DryRun() {
+		return withMarkers(nil, dryRunMarker), nil
 	}
-	defer resp.Body.Close()
 
 	return nil, nil
 }
 
-func userBuilder(client *zoom.Client) *userResourceType {
+func userBuilder(client userAPI, protected *protectedPrincipals, statuses []string, deprovision deprovisioning, resendInvites, details bool) *userResourceType {
 	return &userResourceType{
-		resourceType: resourceTypeUser,
-		client:       client,
+		resourceType:  resourceTypeUser,
+		client:        client,
+		protected:     protected,
+		statuses:      statuses,
+		deprovision:   deprovision,
+		resendInvites: resendInvites,
+		details:       details,
 	}
 }
@@ -8,270 +8,232 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 
-	"github.com/conductorone/baton-sdk/pkg/uhttp"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
 )
 
 type Client struct {
-	httpClient *http.Client
-	token      string
+	httpClient  *http.Client
+	baseUrl     string
+	tokenSource *TokenSource
+	retryPolicy RetryPolicy
+	limiter     *rateLimiter
+	pageSize    int
+	dryRun      bool
 }
 
-const (
-	baseUrl          = "https://api.zoom.us/v2"
-	authUrl          = "https://zoom.us/oauth/token"
-	resourcePageSize = "50"
-)
+type ClientOption func(*Client)
 
-func NewClient(httpClient *http.Client, token string) *Client {
-	return &Client{
-		httpClient: httpClient,
-		token:      token,
+// WithRateLimits throttles requests per Zoom rate limit category, see DefaultRateLimits.
+func WithRateLimits(limits RateLimits) ClientOption {
+	return func(c *Client) {
+		c.limiter = newRateLimiter(limits)
 	}
 }
 
-type Payload struct {
-	ID string `json:"id"`
-}
-
-type PaginationData struct {
-	NextPageToken string `json:"next_page_token"`
-	PageSize      int    `json:"page_size"`
-	TotalRecords  int    `json:"total_records"`
+// WithPageSize sets the page_size of list requests, clamped to Zoom's maximum of 300.
+func WithPageSize(pageSize int) ClientOption {
+	return func(c *Client) {
+		if pageSize > 0 {
+			c.pageSize = min(pageSize, MaxPageSize)
+		}
+	}
 }
 
-// returns query params with pagination options.
-func paginationQuery(nextToken string) url.Values {
-	q := url.Values{}
-	q.Add("next_page_token", nextToken)
-	q.Add("page_size", resourcePageSize)
-	return q
+// WithRetryPolicy overrides DefaultRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
 }
 
-// RequestAccessToken creates bearer token needed to use the Zoom API.
-func RequestAccessToken(ctx context.Context, accountId string, clientId string, clientSecret string) (string, error) {
-	httpClient, err := uhttp.NewClient(ctx, uhttp.WithLogger(true, ctxzap.Extract(ctx)))
-	if err != nil {
-		return "", err
+// WithDryRun logs requests that would change anything in Zoom instead of sending them.
+// Reads are still sent, the skipped requests answer with an empty 204.
+func WithDryRun() ClientOption {
+	return func(c *Client) {
+		c.dryRun = true
 	}
+}
 
-	data := url.Values{}
-	data.Add("account_id", accountId)
-	data.Add("grant_type", "account_credentials")
+// DryRun reports whether the client only logs changes instead of sending them.
+func (c *Client) DryRun() bool {
+	return c.dryRun
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, authUrl, nil)
-	if err != nil {
-		return "", err
+// NewClient creates a client for the API at baseUrl, e.g. Endpoints.APIBaseURL.
+func NewClient(httpClient *http.Client, baseUrl string, tokenSource *TokenSource, opts ...ClientOption) *Client {
+	c := &Client{
+		httpClient:  httpClient,
+		baseUrl:     strings.TrimSuffix(baseUrl, "/"),
+		tokenSource: tokenSource,
+		retryPolicy: DefaultRetryPolicy(),
+		pageSize:    DefaultPageSize,
 	}
 
-	req.Header.Add("accept", "application/json")
-	req.SetBasicAuth(clientId, clientSecret)
-	req.URL.RawQuery = data.Encode()
-
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return "", err
+	for _, opt := range opts {
+		opt(c)
 	}
 
-	defer resp.Body.Close()
+	return c
+}
 
-	var res struct {
-		AccessToken string `json:"Access_token"`
-	}
+type Payload struct {
+	ID string `json:"id"`
+}
 
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return "", err
+// Users lists all Zoom users with their custom attributes.
+// Without a status Zoom only lists active users.
+func (c *Client) Users(status string) *Pager[User] {
+	query := url.Values{"include_fields": {"custom_attributes"}}
+	if status != "" {
+		query.Set("status", status)
 	}
-	return res.AccessToken, nil
+
+	return newPager[User](c, CategoryMedium, fmt.Sprint(c.baseUrl, "/users"), "users", query)
 }
 
-// GetUsers returns all Zoom users.
-func (c *Client) GetUsers(ctx context.Context, nextToken string) ([]User, string, *http.Response, error) {
-	url := fmt.Sprint(baseUrl, "/users")
-	var res struct {
-		PaginationData
-		Users []User `json:"users"`
-	}
+// GetUsers returns a page of Zoom users with a status.
+func (c *Client) GetUsers(ctx context.Context, status string, nextToken string) (*Page[User], error) {
+	return c.Users(status).Page(ctx, nextToken)
+}
 
-	q := paginationQuery(nextToken)
-	resp, err := c.doRequest(ctx, url, &res, http.MethodGet, q, nil)
-	if err != nil {
-		return nil, "", nil, err
-	}
+// Groups lists all Zoom groups.
+func (c *Client) Groups() *Pager[Group] {
+	return newPager[Group](c, CategoryMedium, fmt.Sprint(c.baseUrl, "/groups"), "groups", nil)
+}
 
-	if res.NextPageToken != "" {
-		return res.Users, res.NextPageToken, resp, nil
-	}
+// GetGroups returns a page of Zoom groups.
+func (c *Client) GetGroups(ctx context.Context, nextToken string) (*Page[Group], error) {
+	return c.Groups().Page(ctx, nextToken)
+}
 
-	return res.Users, "", resp, nil
+// ContactGroups lists all contact groups from Zoom.
+func (c *Client) ContactGroups() *Pager[ContactGroup] {
+	return newPager[ContactGroup](c, CategoryMedium, fmt.Sprint(c.baseUrl, "/contacts/groups"), "groups", nil)
 }
 
-// GetGroups returns all Zoom groups.
-func (c *Client) GetGroups(ctx context.Context, nextToken string) ([]Group, string, *http.Response, error) {
-	url := fmt.Sprint(baseUrl, "/groups")
-	var res struct {
-		PaginationData
-		Groups []Group `json:"groups"`
-	}
+// GetContactGroups returns a page of contact groups from Zoom.
+func (c *Client) GetContactGroups(ctx context.Context, nextToken string) (*Page[ContactGroup], error) {
+	return c.ContactGroups().Page(ctx, nextToken)
+}
 
-	q := paginationQuery(nextToken)
-	resp, err := c.doRequest(ctx, url, &res, http.MethodGet, q, nil)
-	if err != nil {
-		return nil, "", nil, err
+// Roles lists all Zoom roles of a type, Zoom only lists common roles if roleType is empty.
+func (c *Client) Roles(roleType string) *Pager[Role] {
+	var query url.Values
+	if roleType != "" {
+		query = url.Values{"type": {roleType}}
 	}
 
-	if res.NextPageToken != "" {
-		return res.Groups, res.NextPageToken, resp, nil
-	}
+	return newPager[Role](c, CategoryMedium, fmt.Sprint(c.baseUrl, "/roles"), "roles", query)
+}
 
-	return res.Groups, "", resp, nil
+// GetRoles returns a page of Zoom roles of a type.
+func (c *Client) GetRoles(ctx context.Context, roleType string, nextToken string) (*Page[Role], error) {
+	return c.Roles(roleType).Page(ctx, nextToken)
 }
 
-// GetContactGroups returns all contact groups from Zoom.
-func (c *Client) GetContactGroups(ctx context.Context, nextToken string) ([]ContactGroup, string, *http.Response, error) {
-	url := fmt.Sprint(baseUrl, "/contacts/groups")
-	var res struct {
-		PaginationData
-		Groups []ContactGroup `json:"groups"`
-	}
+// GetRole returns a role along with its privileges.
+func (c *Client) GetRole(ctx context.Context, roleId string) (*Role, error) {
+	url := fmt.Sprint(c.baseUrl, "/roles/", roleId)
+	var res Role
 
-	q := paginationQuery(nextToken)
-	resp, err := c.doRequest(ctx, url, &res, http.MethodGet, q, nil)
+	_, err := c.doRequest(ctx, CategoryLight, url, &res, http.MethodGet, nil, nil)
 	if err != nil {
-		return nil, "", nil, err
-	}
-
-	if res.NextPageToken != "" {
-		return res.Groups, res.NextPageToken, resp, nil
+		return nil, err
 	}
 
-	return res.Groups, "", resp, nil
+	return &res, nil
 }
 
-// GetRoles returns all Zoom roles.
-func (c *Client) GetRoles(ctx context.Context) ([]Role, *http.Response, error) {
-	url := fmt.Sprint(baseUrl, "/roles")
-	var res struct {
-		Roles []Role `json:"roles"`
+// CreateRole creates a custom role.
+func (c *Client) CreateRole(ctx context.Context, role *RoleBody) (*Role, error) {
+	url := fmt.Sprint(c.baseUrl, "/roles")
+
+	requestBody, err := json.Marshal(role)
+	if err != nil {
+		return nil, err
 	}
 
-	resp, err := c.doRequest(ctx, url, &res, http.MethodGet, nil, nil)
+	var res Role
+	_, err = c.doRequest(ctx, CategoryLight, url, &res, http.MethodPost, nil, requestBody)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	return res.Roles, resp, nil
+	return &res, nil
 }
 
-// GetGroupMembers returns all Zoom group members.
-func (c *Client) GetGroupMembers(ctx context.Context, groupId string) ([]User, error) {
-	url := fmt.Sprintf("%s/groups/%s/members", baseUrl, groupId)
-	var token = ""
-	var members []User
-
-	for {
-		var res struct {
-			PaginationData
-			Members []User `json:"members"`
-		}
-
-		q := paginationQuery(token)
-		resp, err := c.doRequest(ctx, url, &res, http.MethodGet, q, nil)
-		if err != nil {
-			return nil, err
-		}
-		defer resp.Body.Close()
-		members = append(members, res.Members...)
-
-		if res.NextPageToken == "" {
-			break
-		}
+// UpdateRole changes the name, description or privileges of a custom role.
+func (c *Client) UpdateRole(ctx context.Context, roleId string, role *RoleBody) error {
+	url := fmt.Sprint(c.baseUrl, "/roles/", roleId)
 
-		token = res.NextPageToken
+	requestBody, err := json.Marshal(role)
+	if err != nil {
+		return err
 	}
 
-	return members, nil
-}
-
-// GetGroupAdmins returns all Zoom group admins.
-func (c *Client) GetGroupAdmins(ctx context.Context, groupId string) ([]User, error) {
-	url := fmt.Sprintf("%s/groups/%s/admins", baseUrl, groupId)
-	var admins []User
-	var token = ""
-
-	for {
-		var res struct {
-			PaginationData
-			Admins []User `json:"admins"`
-		}
+	_, err = c.doRequest(ctx, CategoryLight, url, nil, http.MethodPatch, nil, requestBody)
 
-		q := paginationQuery(token)
-		resp, err := c.doRequest(ctx, url, &res, http.MethodGet, q, nil)
-		if err != nil {
-			return nil, err
-		}
-		defer resp.Body.Close()
+	return err
+}
 
-		admins = append(admins, res.Admins...)
+// DeleteRole deletes a custom role.
+func (c *Client) DeleteRole(ctx context.Context, roleId string) error {
+	url := fmt.Sprint(c.baseUrl, "/roles/", roleId)
 
-		if res.NextPageToken == "" {
-			break
-		}
+	_, err := c.doRequest(ctx, CategoryLight, url, nil, http.MethodDelete, nil, nil)
 
-		token = res.NextPageToken
-	}
-	return admins, nil
+	return err
 }
 
-// GetContactGroupMembers returns all Zoom contact group members.
-func (c *Client) GetContactGroupMembers(ctx context.Context, groupId string, nextToken string) ([]GroupMember, string, *http.Response, error) {
-	url := fmt.Sprintf("%s/contacts/groups/%s/members", baseUrl, groupId)
-	var res struct {
-		PaginationData
-		Members []GroupMember `json:"group_members"`
-	}
+// GroupMembers lists all members of a Zoom group.
+func (c *Client) GroupMembers(groupId string) *Pager[User] {
+	return newPager[User](c, CategoryMedium, fmt.Sprintf("%s/groups/%s/members", c.baseUrl, groupId), "members", nil)
+}
 
-	q := paginationQuery(nextToken)
-	resp, err := c.doRequest(ctx, url, &res, http.MethodGet, q, nil)
-	if err != nil {
-		return nil, "", nil, err
-	}
+// GetGroupMembers returns all Zoom group members.
+func (c *Client) GetGroupMembers(ctx context.Context, groupId string) ([]User, error) {
+	return c.GroupMembers(groupId).Collect(ctx)
+}
 
-	if res.NextPageToken != "" {
-		return res.Members, res.NextPageToken, resp, nil
-	}
+// GroupAdmins lists all admins of a Zoom group.
+func (c *Client) GroupAdmins(groupId string) *Pager[User] {
+	return newPager[User](c, CategoryMedium, fmt.Sprintf("%s/groups/%s/admins", c.baseUrl, groupId), "admins", nil)
+}
 
-	return res.Members, "", resp, nil
+// GetGroupAdmins returns all Zoom group admins.
+func (c *Client) GetGroupAdmins(ctx context.Context, groupId string) ([]User, error) {
+	return c.GroupAdmins(groupId).Collect(ctx)
 }
 
-// GetRoleMembers returns all Zoom role members.
-func (c *Client) GetRoleMembers(ctx context.Context, roleId string, nextToken string) ([]User, string, *http.Response, error) {
-	url := fmt.Sprintf("%s/roles/%s/members", baseUrl, roleId)
-	var res struct {
-		PaginationData
-		Members []User `json:"members"`
-	}
+// ContactGroupMembers lists all members of a Zoom contact group.
+func (c *Client) ContactGroupMembers(groupId string) *Pager[GroupMember] {
+	return newPager[GroupMember](c, CategoryMedium, fmt.Sprintf("%s/contacts/groups/%s/members", c.baseUrl, groupId), "group_members", nil)
+}
 
-	q := paginationQuery(nextToken)
-	resp, err := c.doRequest(ctx, url, &res, http.MethodGet, q, nil)
-	if err != nil {
-		return nil, "", nil, err
-	}
+// GetContactGroupMembers returns a page of Zoom contact group members.
+func (c *Client) GetContactGroupMembers(ctx context.Context, groupId string, nextToken string) (*Page[GroupMember], error) {
+	return c.ContactGroupMembers(groupId).Page(ctx, nextToken)
+}
 
-	if res.NextPageToken != "" {
-		return res.Members, res.NextPageToken, resp, nil
-	}
+// RoleMembers lists all members of a Zoom role.
+func (c *Client) RoleMembers(roleId string) *Pager[User] {
+	return newPager[User](c, CategoryMedium, fmt.Sprintf("%s/roles/%s/members", c.baseUrl, roleId), "members", nil)
+}
 
-	return res.Members, "", resp, nil
+// GetRoleMembers returns a page of Zoom role members.
+func (c *Client) GetRoleMembers(ctx context.Context, roleId string, nextToken string) (*Page[User], error) {
+	return c.RoleMembers(roleId).Page(ctx, nextToken)
 }
 
 // GetUser returns user details.
 func (c *Client) GetUser(ctx context.Context, userId string) (User, *http.Response, error) {
-	url := fmt.Sprint(baseUrl, "/users/", userId)
+	url := fmt.Sprint(c.baseUrl, "/users/", userId)
 	var res User
 
-	resp, err := c.doRequest(ctx, url, &res, http.MethodGet, nil, nil)
+	resp, err := c.doRequest(ctx, CategoryLight, url, &res, http.MethodGet, nil, nil)
 	if err != nil {
 		return User{}, nil, err
 	}
@@ -281,7 +243,7 @@ func (c *Client) GetUser(ctx context.Context, userId string) (User, *http.Respon
 
 // AddGroupMembers adds user to a group.
 func (c *Client) AddGroupMembers(ctx context.Context, groupId, userId string) error {
-	url := fmt.Sprint(baseUrl, "/groups/", groupId, "/members")
+	url := fmt.Sprint(c.baseUrl, "/groups/", groupId, "/members")
 	members := []Payload{
 		{
 			ID: userId,
@@ -298,7 +260,7 @@ func (c *Client) AddGroupMembers(ctx context.Context, groupId, userId string) er
 	var res struct {
 		MemberIDs []string `json:"member_ids"`
 	}
-	resp, e := c.doRequest(ctx, url, &res, http.MethodPost, nil, requestBody)
+	resp, e := c.doRequest(ctx, CategoryMedium, url, &res, http.MethodPost, nil, requestBody)
 	if e != nil {
 		return e
 	}
@@ -310,7 +272,7 @@ func (c *Client) AddGroupMembers(ctx context.Context, groupId, userId string) er
 
 // AddGroupAdmins adds admin to the group.
 func (c *Client) AddGroupAdmins(ctx context.Context, groupId, userId string) error {
-	url := fmt.Sprint(baseUrl, "/groups/", groupId, "/admins")
+	url := fmt.Sprint(c.baseUrl, "/groups/", groupId, "/admins")
 	members := []Payload{
 		{
 			ID: userId,
@@ -327,7 +289,7 @@ func (c *Client) AddGroupAdmins(ctx context.Context, groupId, userId string) err
 	var res struct {
 		MemberIDs []string `json:"member_ids"`
 	}
-	resp, e := c.doRequest(ctx, url, &res, http.MethodPost, nil, requestBody)
+	resp, e := c.doRequest(ctx, CategoryLight, url, &res, http.MethodPost, nil, requestBody)
 	if e != nil {
 		return e
 	}
@@ -339,9 +301,9 @@ func (c *Client) AddGroupAdmins(ctx context.Context, groupId, userId string) err
 
 // DeleteGroupAdmin removes admin from the group.
 func (c *Client) DeleteGroupAdmin(ctx context.Context, groupId, userId string) error {
-	url := fmt.Sprint(baseUrl, "/groups/", groupId, "/admins/", userId)
+	url := fmt.Sprint(c.baseUrl, "/groups/", groupId, "/admins/", userId)
 
-	resp, err := c.doRequest(ctx, url, nil, http.MethodDelete, nil, nil)
+	resp, err := c.doRequest(ctx, CategoryLight, url, nil, http.MethodDelete, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -353,9 +315,9 @@ func (c *Client) DeleteGroupAdmin(ctx context.Context, groupId, userId string) e
 
 // DeleteGroupMember removes member from the group.
 func (c *Client) DeleteGroupMember(ctx context.Context, groupId, userId string) error {
-	url := fmt.Sprint(baseUrl, "/groups/", groupId, "/members/", userId)
+	url := fmt.Sprint(c.baseUrl, "/groups/", groupId, "/members/", userId)
 
-	resp, err := c.doRequest(ctx, url, nil, http.MethodDelete, nil, nil)
+	resp, err := c.doRequest(ctx, CategoryLight, url, nil, http.MethodDelete, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -367,7 +329,7 @@ func (c *Client) DeleteGroupMember(ctx context.Context, groupId, userId string)
 
 // AssignRole assigns role to a user.
 func (c *Client) AssignRole(ctx context.Context, roleId, userId string) error {
-	url := fmt.Sprint(baseUrl, "/roles/", roleId, "/members")
+	url := fmt.Sprint(c.baseUrl, "/roles/", roleId, "/members")
 	members := []Payload{
 		{
 			ID: userId,
@@ -386,7 +348,7 @@ func (c *Client) AssignRole(ctx context.Context, roleId, userId string) error {
 		AddAt string `json:"add_at"`
 		IDs   string `json:"ids"`
 	}
-	resp, e := c.doRequest(ctx, url, &res, http.MethodPost, nil, requestBody)
+	resp, e := c.doRequest(ctx, CategoryLight, url, &res, http.MethodPost, nil, requestBody)
 	if e != nil {
 		return e
 	}
@@ -397,9 +359,9 @@ func (c *Client) AssignRole(ctx context.Context, roleId, userId string) error {
 
 // UnassignRole unassigns role from a user.
 func (c *Client) UnassignRole(ctx context.Context, roleId, userId string) error {
-	url := fmt.Sprint(baseUrl, "/roles/", roleId, "/members/", userId)
+	url := fmt.Sprint(c.baseUrl, "/roles/", roleId, "/members/", userId)
 
-	resp, err := c.doRequest(ctx, url, nil, http.MethodDelete, nil, nil)
+	resp, err := c.doRequest(ctx, CategoryLight, url, nil, http.MethodDelete, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -409,8 +371,60 @@ func (c *Client) UnassignRole(ctx context.Context, roleId, userId string) error
 	return nil
 }
 
+// UpdateUser changes a user, e.g. their user type.
+func (c *Client) UpdateUser(ctx context.Context, userId string, update *UserUpdateBody) error {
+	requestURL, err := url.JoinPath(c.baseUrl, "users", userId)
+	if err != nil {
+		return err
+	}
+
+	requestBody, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(ctx, CategoryLight, requestURL, nil, http.MethodPatch, nil, requestBody)
+
+	return err
+}
+
+// GetUserSettings returns the settings of a user, including the add-on plans they have.
+func (c *Client) GetUserSettings(ctx context.Context, userId string) (*UserSettings, error) {
+	requestURL, err := url.JoinPath(c.baseUrl, "users", userId, "settings")
+	if err != nil {
+		return nil, err
+	}
+
+	var res UserSettings
+	_, err = c.doRequest(ctx, CategoryMedium, requestURL, &res, http.MethodGet, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// UpdateUserFeatures turns add-on plans of a user on or off, e.g. {"webinar": true, "webinar_capacity": 500}.
+func (c *Client) UpdateUserFeatures(ctx context.Context, userId string, features map[string]interface{}) error {
+	requestURL, err := url.JoinPath(c.baseUrl, "users", userId, "settings")
+	if err != nil {
+		return err
+	}
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"feature": features,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(ctx, CategoryMedium, requestURL, nil, http.MethodPatch, nil, requestBody)
+
+	return err
+}
+
 func (c *Client) CreateUser(ctx context.Context, newUser *UserCreationBody) (*UserCreationResponse, error) {
-	requestURL, err := url.JoinPath(baseUrl, "users")
+	requestURL, err := url.JoinPath(c.baseUrl, "users")
 	if err != nil {
 		return nil, err
 	}
@@ -421,7 +435,7 @@ func (c *Client) CreateUser(ctx context.Context, newUser *UserCreationBody) (*Us
 	}
 
 	var res UserCreationResponse
-	resp, err := c.doRequest(ctx, requestURL, &res, http.MethodPost, nil, requestBody)
+	resp, err := c.doRequest(ctx, CategoryLight, requestURL, &res, http.MethodPost, nil, requestBody)
 	if err != nil {
 		return nil, err
 	}
@@ -430,13 +444,14 @@ func (c *Client) CreateUser(ctx context.Context, newUser *UserCreationBody) (*Us
 	return &res, nil
 }
 
-func (c *Client) DeleteUser(ctx context.Context, userId string) error {
-	requestURL, err := url.JoinPath(baseUrl, "users", userId)
+// DeleteUser removes a user from the account, disassociating them unless opts say otherwise.
+func (c *Client) DeleteUser(ctx context.Context, userId string, opts DeleteUserOptions) error {
+	requestURL, err := url.JoinPath(c.baseUrl, "users", userId)
 	if err != nil {
 		return err
 	}
 
-	resp, err := c.doRequest(ctx, requestURL, nil, http.MethodDelete, nil, nil)
+	resp, err := c.doRequest(ctx, CategoryLight, requestURL, nil, http.MethodDelete, opts.query(), nil)
 	if err != nil {
 		return err
 	}
@@ -445,38 +460,106 @@ func (c *Client) DeleteUser(ctx context.Context, userId string) error {
 	return nil
 }
 
-func (c *Client) doRequest(ctx context.Context, url string, res interface{}, method string, params url.Values, payload []byte) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
+// UpdateUserStatus activates or deactivates a user, see ActivateUser and DeactivateUser.
+func (c *Client) UpdateUserStatus(ctx context.Context, userId string, action string) error {
+	requestURL, err := url.JoinPath(c.baseUrl, "users", userId, "status")
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	if params != nil {
-		req.URL.RawQuery = params.Encode()
+	requestBody, err := json.Marshal(map[string]string{
+		"action": action,
+	})
+	if err != nil {
+		return err
 	}
 
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	_, err = c.doRequest(ctx, CategoryLight, requestURL, nil, http.MethodPut, nil, requestBody)
 
-	resp, err := c.httpClient.Do(req)
+	return err
+}
+
+// RevokeSSOToken revokes the user's SSO token, which signs them out of every Zoom session.
+func (c *Client) RevokeSSOToken(ctx context.Context, userId string) error {
+	requestURL, err := url.JoinPath(c.baseUrl, "users", userId, "token")
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	defer resp.Body.Close()
+	_, err = c.doRequest(ctx, CategoryLight, requestURL, nil, http.MethodDelete, nil, nil)
 
-	b, err := io.ReadAll(resp.Body)
+	return err
+}
+
+func (c *Client) doRequest(ctx context.Context, category RateLimitCategory, url string, res interface{}, method string, params url.Values, payload []byte) (*http.Response, error) {
+	if c.dryRun && method != http.MethodGet {
+		ctxzap.Extract(ctx).Info(
+			"zoom: dry run, request not sent",
+			zap.String("method", method),
+			zap.String("url", url),
+			zap.String("query", params.Encode()),
+			zap.ByteString("body", payload),
+		)
+		return &http.Response{StatusCode: http.StatusNoContent, Header: http.Header{}, Body: http.NoBody}, nil
+	}
+
+	token, err := c.tokenSource.Token(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	var resp *http.Response
+	var b []byte
+	reauthenticated := false
+
+	for attempt := 0; ; {
+		if err := c.limiter.wait(ctx, category); err != nil {
+			return nil, err
+		}
+
+		resp, b, err = c.send(ctx, url, token, method, params, payload)
+		if err != nil {
+			return nil, err
+		}
+
+		// The token may have been revoked or expired early, re-authenticate once and retry.
+		if resp.StatusCode == http.StatusUnauthorized && !reauthenticated {
+			reauthenticated = true
+			c.tokenSource.Invalidate(token)
+
+			token, err = c.tokenSource.Token(ctx)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		delay, ok := c.retryPolicy.retryDelay(resp, attempt)
+		if !ok {
+			break
+		}
+		attempt++
+
+		ctxzap.Extract(ctx).Debug(
+			"zoom: retrying request",
+			zap.String("method", method),
+			zap.String("url", url),
+			zap.Int("status_code", resp.StatusCode),
+			zap.Int("attempt", attempt),
+			zap.Duration("delay", delay),
+		)
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+
 	if len(b) == 0 && resp.StatusCode >= 200 && resp.StatusCode < 400 {
 		return resp, nil
 	}
 
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("request failed with status code %d: %s", resp.StatusCode, string(b))
+		return nil, newAPIError(resp, b)
 	}
 
 	if err := json.Unmarshal(b, &res); err != nil {
@@ -485,3 +568,33 @@ func (c *Client) doRequest(ctx context.Context, url string, res interface{}, met
 
 	return resp, nil
 }
+
+// send performs a single request and returns the response along with its fully read body.
+func (c *Client) send(ctx context.Context, url string, token string, method string, params url.Values, payload []byte) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if params != nil {
+		req.URL.RawQuery = params.Encode()
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resp, b, nil
+}
@@ -0,0 +1,65 @@
+package zoom
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Environment is the Zoom cloud an account lives in.
+type Environment string
+
+const (
+	EnvironmentCommercial Environment = "commercial"
+	EnvironmentGov        Environment = "gov"
+)
+
+var Environments = []string{
+	string(EnvironmentCommercial),
+	string(EnvironmentGov),
+}
+
+// Endpoints are the URLs the client talks to.
+type Endpoints struct {
+	APIBaseURL string
+	OAuthURL   string
+}
+
+// EndpointsFor returns the public endpoints of the given environment.
+func EndpointsFor(env Environment) (Endpoints, error) {
+	switch env {
+	case EnvironmentCommercial:
+		return Endpoints{
+			APIBaseURL: "https://api.zoom.us/v2",
+			OAuthURL:   "https://zoom.us/oauth/token",
+		}, nil
+	case EnvironmentGov:
+		return Endpoints{
+			APIBaseURL: "https://api.zoomgov.com/v2",
+			OAuthURL:   "https://zoomgov.com/oauth/token",
+		}, nil
+	default:
+		return Endpoints{}, fmt.Errorf("unknown zoom environment %q", env)
+	}
+}
+
+// WithOverrides replaces the endpoints that are set, e.g. to point at a local stand-in API.
+func (e Endpoints) WithOverrides(apiBaseURL, oauthURL string) (Endpoints, error) {
+	for _, u := range []*string{&apiBaseURL, &oauthURL} {
+		if *u == "" {
+			continue
+		}
+		parsed, err := url.Parse(*u)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return Endpoints{}, fmt.Errorf("invalid zoom endpoint %q, expected an absolute http(s) URL", *u)
+		}
+	}
+
+	if apiBaseURL != "" {
+		e.APIBaseURL = apiBaseURL
+	}
+	if oauthURL != "" {
+		e.OAuthURL = oauthURL
+	}
+
+	return e, nil
+}
@@ -0,0 +1,213 @@
+package zoom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const trackingIdHeader = "x-zm-trackingid"
+
+// Zoom error codes the connector reacts to, as listed in the error tables of the Zoom API reference
+// (https://developers.zoom.us/docs/api/) for the endpoints named below.
+const (
+	// User does not exist, GET, PATCH and DELETE /users/{userId} and the group and role member endpoints.
+	ErrorCodeUserNotFound = 1001
+	// User already in the account, POST /users.
+	ErrorCodeUserAlreadyExists = 1005
+	// Group does not exist, the /groups/{groupId} endpoints.
+	ErrorCodeGroupNotFound = 4130
+	// The user is already a member (or admin) of the group, POST /groups/{groupId}/members and /admins.
+	ErrorCodeGroupMemberExists = 4132
+	ErrorCodeGroupAdminExists  = 4133
+	// The user is not a member (or admin) of the group, DELETE /groups/{groupId}/members/{memberId} and /admins/{userId}.
+	ErrorCodeGroupMemberNotFound = 4136
+	ErrorCodeGroupAdminNotFound  = 4137
+	// The user already has the role, or doesn't have it, POST /roles/{roleId}/members and DELETE /roles/{roleId}/members/{memberId}.
+	ErrorCodeRoleMemberExists   = 4140
+	ErrorCodeRoleMemberNotFound = 4141
+	// The account has no seats left for the user type or add-on, POST /users and PATCH /users/{userId}.
+	ErrorCodeNotEnoughLicenses = 2034
+	// The request is invalid for the account, e.g. listing IQ or phone roles without Revenue Accelerator or Zoom Phone.
+	ErrorCodeValidationFailed = 300
+)
+
+// ErrDailyLimitExceeded matches (via errors.Is) an APIError caused by exhausting the account's daily request quota.
+var ErrDailyLimitExceeded = errors.New("zoom daily rate limit exceeded")
+
+// APIError is returned for any non-successful response from the Zoom API.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Code is the Zoom specific error code, e.g. 1001 for "User does not exist".
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	// TrackingID is the value of the x-zm-trackingid header, used by Zoom support to trace a request.
+	TrackingID string
+	// RateLimitType is the X-RateLimit-Type header of a 429, either "QPS" or "Daily-limit".
+	RateLimitType string
+	// RetryAfter is the parsed Retry-After header, zero if it wasn't sent.
+	RetryAfter time.Duration
+}
+
+// newAPIError builds an APIError from a failed response and its already read body.
+// Bodies that are not in Zoom's {code, message} format are kept verbatim as the message.
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		TrackingID: resp.Header.Get(trackingIdHeader),
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		e.RateLimitType = resp.Header.Get(rateLimitTypeHeader)
+	}
+	if retryAfter, ok := parseRetryAfter(resp.Header.Get(retryAfterHeader)); ok {
+		e.RetryAfter = retryAfter
+	}
+
+	if err := json.Unmarshal(body, e); err != nil || e.Message == "" {
+		e.Message = string(body)
+	}
+
+	return e
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("request failed with status code %d", e.StatusCode)
+	if e.Code != 0 {
+		msg = fmt.Sprintf("%s: code %d", msg, e.Code)
+	}
+	if e.Message != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Message)
+	}
+	if e.RetryAfter > 0 {
+		msg = fmt.Sprintf("%s (retry after %s)", msg, e.RetryAfter)
+	}
+	if e.TrackingID != "" {
+		msg = fmt.Sprintf("%s (tracking id %s)", msg, e.TrackingID)
+	}
+
+	return msg
+}
+
+func (e *APIError) isDailyLimit() bool {
+	return e.StatusCode == http.StatusTooManyRequests && e.RateLimitType == rateLimitTypeDaily
+}
+
+func (e *APIError) Is(target error) bool {
+	return target == ErrDailyLimitExceeded && e.isDailyLimit()
+}
+
+// IsAlreadyMember reports whether err means the user already holds the group
+// membership, group admin or role that was being granted.
+func IsAlreadyMember(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch apiErr.Code {
+	case ErrorCodeGroupMemberExists, ErrorCodeGroupAdminExists, ErrorCodeRoleMemberExists:
+		return true
+	}
+
+	return false
+}
+
+// IsNotMember reports whether err means the user doesn't hold the group
+// membership, group admin or role that was being revoked.
+func IsNotMember(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch apiErr.Code {
+	case ErrorCodeGroupMemberNotFound, ErrorCodeGroupAdminNotFound, ErrorCodeRoleMemberNotFound:
+		return true
+	}
+
+	return false
+}
+
+// IsUserNotFound reports whether err means the user no longer exists in Zoom,
+// so any membership they held is gone with them.
+func IsUserNotFound(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	return apiErr.Code == ErrorCodeUserNotFound
+}
+
+// GRPCStatus lets status.Code and the baton runtime classify Zoom errors.
+func (e *APIError) GRPCStatus() *status.Status {
+	var code codes.Code
+
+	switch e.StatusCode {
+	case http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusForbidden:
+		code = codes.PermissionDenied
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusConflict:
+		code = codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		// Unlike the per-second limits, the daily quota won't recover by retrying soon.
+		if e.isDailyLimit() {
+			code = codes.ResourceExhausted
+		} else {
+			code = codes.Unavailable
+		}
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		code = codes.Unavailable
+	case http.StatusInternalServerError:
+		code = codes.Internal
+	default:
+		code = codes.Unknown
+	}
+
+	return status.New(code, e.Error())
+}
+
+// IsOutOfLicenses reports whether err means the account has no seats left for
+// the user type or add-on that was being assigned.
+func IsOutOfLicenses(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	return apiErr.Code == ErrorCodeNotEnoughLicenses
+}
+
+// IsUserAlreadyExists reports whether err means a user with the email being
+// created is already in the account, possibly with a pending invitation.
+func IsUserAlreadyExists(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	return apiErr.Code == ErrorCodeUserAlreadyExists
+}
+
+// IsFeatureNotEnabled reports whether err means the account doesn't have the
+// product behind the request, as Zoom answers listing IQ or phone roles then.
+func IsFeatureNotEnabled(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	return apiErr.StatusCode == http.StatusBadRequest && apiErr.Code == ErrorCodeValidationFailed
+}
@@ -0,0 +1,86 @@
+package zoom
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAPIErrorGRPCStatus(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		want       codes.Code
+	}{
+		{http.StatusBadRequest, codes.InvalidArgument},
+		{http.StatusForbidden, codes.PermissionDenied},
+		{http.StatusNotFound, codes.NotFound},
+		{http.StatusConflict, codes.AlreadyExists},
+		{http.StatusTooManyRequests, codes.Unavailable},
+		{http.StatusTeapot, codes.Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			err := fmt.Errorf("baton-zoom: wrapped: %w", &APIError{StatusCode: tt.statusCode})
+			require.Equal(t, tt.want, status.Code(err))
+		})
+	}
+}
+
+func TestDoRequestReturnsAPIError(t *testing.T) {
+	tokenSrv, _ := newTokenServer(t, 3600)
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(trackingIdHeader, "v=2.0;clid=us06;rid=WEB_123")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"code":1001,"message":"User does not exist: abc."}`)
+	}))
+	defer api.Close()
+
+	c := NewClient(api.Client(), api.URL, newTestTokenSource(tokenSrv))
+
+	_, err := c.doRequest(context.Background(), CategoryLight, api.URL, nil, http.MethodGet, nil, nil)
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	require.Equal(t, 1001, apiErr.Code)
+	require.Equal(t, "User does not exist: abc.", apiErr.Message)
+	require.Equal(t, "v=2.0;clid=us06;rid=WEB_123", apiErr.TrackingID)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestMembershipErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    int
+		body          string
+		alreadyMember bool
+		notMember     bool
+		userNotFound  bool
+	}{
+		{name: "group member exists", statusCode: http.StatusBadRequest, body: `{"code":4132,"message":"User is already in the group."}`, alreadyMember: true},
+		{name: "group admin exists", statusCode: http.StatusBadRequest, body: `{"code":4133,"message":"User is already an admin of the group."}`, alreadyMember: true},
+		{name: "role member exists", statusCode: http.StatusBadRequest, body: `{"code":4140,"message":"User already has the role."}`, alreadyMember: true},
+		{name: "group member not found", statusCode: http.StatusNotFound, body: `{"code":4136,"message":"User is not in the group."}`, notMember: true},
+		{name: "group admin not found", statusCode: http.StatusNotFound, body: `{"code":4137,"message":"User is not an admin of the group."}`, notMember: true},
+		{name: "role member not found", statusCode: http.StatusNotFound, body: `{"code":4141,"message":"User does not have the role."}`, notMember: true},
+		{name: "user not found", statusCode: http.StatusNotFound, body: `{"code":1001,"message":"User does not exist: abc."}`, userNotFound: true},
+		{name: "group not found", statusCode: http.StatusNotFound, body: `{"code":4130,"message":"Group does not exist: abc."}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("baton-zoom: wrapped: %w", newAPIError(&http.Response{StatusCode: tt.statusCode, Header: http.Header{}}, []byte(tt.body)))
+			require.Equal(t, tt.alreadyMember, IsAlreadyMember(err))
+			require.Equal(t, tt.notMember, IsNotMember(err))
+			require.Equal(t, tt.userNotFound, IsUserNotFound(err))
+		})
+	}
+}
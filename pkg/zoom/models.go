@@ -1,5 +1,7 @@
 package zoom
 
+import "net/url"
+
 type ActionType string
 type UserType int
 
@@ -34,11 +36,56 @@ type Member struct {
 	Type      int    `json:"type"`
 }
 
+// User statuses, each listed separately by Zoom.
+const (
+	UserStatusActive   = "active"
+	UserStatusInactive = "inactive"
+	// UserStatusPending users haven't accepted their invitation yet.
+	UserStatusPending = "pending"
+)
+
+var UserStatuses = []string{UserStatusActive, UserStatusInactive, UserStatusPending}
+
+// Role types, each listed separately by Zoom.
+const (
+	RoleTypeCommon = "common"
+	// RoleTypeIQ roles grant access to Zoom Revenue Accelerator.
+	RoleTypeIQ = "iq"
+	// RoleTypePhone roles grant admin access to Zoom Phone.
+	RoleTypePhone = "phone"
+)
+
+var RoleTypes = []string{RoleTypeCommon, RoleTypeIQ, RoleTypePhone}
+
+// IDs of Zoom's built-in common roles, which can't be changed or deleted.
+const (
+	OwnerRoleID  = "0"
+	AdminRoleID  = "1"
+	MemberRoleID = "2"
+)
+
+// IsBuiltInRole reports whether roleId is one of Zoom's built-in roles.
+func IsBuiltInRole(roleId string) bool {
+	return roleId == OwnerRoleID || roleId == AdminRoleID || roleId == MemberRoleID
+}
+
+// RoleBody is the payload to create or update a custom role.
+type RoleBody struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Type is only set on creation, Zoom defaults to common.
+	Type       string   `json:"type,omitempty"`
+	Privileges []string `json:"privileges,omitempty"`
+}
+
 type Role struct {
-	Description string `json:"description"`
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Type        string `json:"type"`
+	Description  string `json:"description"`
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	TotalMembers int    `json:"total_members,omitempty"`
+	// Privileges such as "User:Edit" are only returned when getting a single role.
+	Privileges []string `json:"privileges,omitempty"`
 }
 
 type User struct {
@@ -51,6 +98,106 @@ type User struct {
 	DisplayName string `json:"display_name"`
 	RoleID      string `json:"role_id"`
 	Status      string `json:"status"`
+
+	Department        string `json:"dept,omitempty"`
+	Timezone          string `json:"timezone,omitempty"`
+	CreatedAt         string `json:"created_at,omitempty"`
+	LastLoginTime     string `json:"last_login_time,omitempty"`
+	LastClientVersion string `json:"last_client_version,omitempty"`
+	Verified          int    `json:"verified,omitempty"`
+	PMI               int64  `json:"pmi,omitempty"`
+	EmployeeUniqueID  string `json:"employee_unique_id,omitempty"`
+	// GroupIDs are the groups the user is a member of, not the ones they administer.
+	GroupIDs []string `json:"group_ids,omitempty"`
+
+	// Only returned when getting a single user.
+	JobTitle         string            `json:"job_title,omitempty"`
+	Manager          string            `json:"manager,omitempty"`
+	Location         string            `json:"location,omitempty"`
+	LoginTypes       []int             `json:"login_types,omitempty"`
+	CustomAttributes []CustomAttribute `json:"custom_attributes,omitempty"`
+}
+
+// CustomAttribute is an account-defined user field, e.g. a cost center.
+type CustomAttribute struct {
+	Key   string `json:"key"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// What happens to a user removed from the account.
+type UserDeleteAction string
+
+const (
+	// DisassociateUser unlinks the user from the account, they keep a Basic account of their own.
+	DisassociateUser UserDeleteAction = "disassociate"
+	// DeleteUser permanently deletes the user and their data.
+	DeleteUser UserDeleteAction = "delete"
+)
+
+// DeleteUserOptions choose how a user is removed and who gets their content.
+type DeleteUserOptions struct {
+	// Action defaults to disassociate.
+	Action UserDeleteAction
+	// TransferEmail receives the content flagged below.
+	TransferEmail       string
+	TransferMeetings    bool
+	TransferWebinars    bool
+	TransferRecordings  bool
+	TransferWhiteboards bool
+}
+
+func (o DeleteUserOptions) query() url.Values {
+	q := url.Values{}
+	if o.Action != "" {
+		q.Set("action", string(o.Action))
+	}
+	if o.TransferEmail == "" {
+		return q
+	}
+
+	q.Set("transfer_email", o.TransferEmail)
+	for param, transfer := range map[string]bool{
+		"transfer_meeting":    o.TransferMeetings,
+		"transfer_webinar":    o.TransferWebinars,
+		"transfer_recording":  o.TransferRecordings,
+		"transfer_whiteboard": o.TransferWhiteboards,
+	} {
+		if transfer {
+			q.Set(param, "true")
+		}
+	}
+
+	return q
+}
+
+// Actions of PUT /users/{userId}/status.
+const (
+	ActivateUser   = "activate"
+	DeactivateUser = "deactivate"
+)
+
+// UserUpdateBody is the payload to change a user, only the set fields are changed.
+type UserUpdateBody struct {
+	Type     UserType `json:"type,omitempty"`
+	Dept     string   `json:"dept,omitempty"`
+	JobTitle string   `json:"job_title,omitempty"`
+}
+
+// UserSettings holds the parts of a user's settings the connector reads.
+type UserSettings struct {
+	Feature UserFeatures `json:"feature"`
+}
+
+// UserFeatures are the add-on plans a user has been given.
+type UserFeatures struct {
+	MeetingCapacity      int  `json:"meeting_capacity,omitempty"`
+	LargeMeeting         bool `json:"large_meeting"`
+	LargeMeetingCapacity int  `json:"large_meeting_capacity,omitempty"`
+	Webinar              bool `json:"webinar"`
+	WebinarCapacity      int  `json:"webinar_capacity,omitempty"`
+	ZoomPhone            bool `json:"zoom_phone"`
+	ZoomWhiteboard       bool `json:"zoom_whiteboard"`
 }
 
 type UserCreationBody struct {
@@ -0,0 +1,123 @@
+package zoom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	DefaultPageSize = 50
+	// MaxPageSize is the largest page_size Zoom accepts on list endpoints.
+	MaxPageSize = 300
+)
+
+// Page is a single page of a Zoom list endpoint.
+type Page[T any] struct {
+	Items         []T
+	NextPageToken string
+	TotalRecords  int
+	// Response carries the rate limit headers of the request that fetched the page.
+	Response *http.Response
+}
+
+// Pager walks a Zoom list endpoint that paginates with next_page_token.
+type Pager[T any] struct {
+	client   *Client
+	category RateLimitCategory
+	url      string
+	// itemsKey is the JSON field holding the items, e.g. "users" or "members".
+	itemsKey string
+	query    url.Values
+}
+
+func newPager[T any](c *Client, category RateLimitCategory, url string, itemsKey string, query url.Values) *Pager[T] {
+	return &Pager[T]{
+		client:   c,
+		category: category,
+		url:      url,
+		itemsKey: itemsKey,
+		query:    query,
+	}
+}
+
+// Page fetches the page starting at pageToken, an empty token is the first page.
+func (p *Pager[T]) Page(ctx context.Context, pageToken string) (*Page[T], error) {
+	q := url.Values{}
+	for k, v := range p.query {
+		q[k] = v
+	}
+	q.Set("page_size", strconv.Itoa(p.client.pageSize))
+	if pageToken != "" {
+		q.Set("next_page_token", pageToken)
+	}
+
+	var raw map[string]json.RawMessage
+	resp, err := p.client.doRequest(ctx, p.category, p.url, &raw, http.MethodGet, q, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page[T]{Response: resp}
+
+	if v, ok := raw["next_page_token"]; ok {
+		if err := json.Unmarshal(v, &page.NextPageToken); err != nil {
+			return nil, fmt.Errorf("failed to parse next_page_token: %w", err)
+		}
+	}
+	if v, ok := raw["total_records"]; ok {
+		if err := json.Unmarshal(v, &page.TotalRecords); err != nil {
+			return nil, fmt.Errorf("failed to parse total_records: %w", err)
+		}
+	}
+	if v, ok := raw[p.itemsKey]; ok {
+		if err := json.Unmarshal(v, &page.Items); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", p.itemsKey, err)
+		}
+	}
+
+	return page, nil
+}
+
+// All iterates over every item of every page. Iteration stops after the first error.
+func (p *Pager[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		pageToken := ""
+		for {
+			page, err := p.Page(ctx, pageToken)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if page.NextPageToken == "" {
+				return
+			}
+			pageToken = page.NextPageToken
+		}
+	}
+}
+
+// Collect reads every page into a single slice.
+func (p *Pager[T]) Collect(ctx context.Context) ([]T, error) {
+	var items []T
+	for item, err := range p.All(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,55 @@
+package zoom
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPagerWalksAllPages(t *testing.T) {
+	tokenSrv, _ := newTokenServer(t, 3600)
+
+	var pageSizes []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pageSizes = append(pageSizes, r.URL.Query().Get("page_size"))
+		switch r.URL.Query().Get("next_page_token") {
+		case "":
+			fmt.Fprint(w, `{"next_page_token":"p2","page_size":2,"total_records":3,"members":[{"id":"a"},{"id":"b"}]}`)
+		case "p2":
+			fmt.Fprint(w, `{"next_page_token":"","page_size":2,"total_records":3,"members":[{"id":"c"}]}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer api.Close()
+
+	c := NewClient(api.Client(), api.URL, newTestTokenSource(tokenSrv), WithPageSize(1000))
+
+	page, err := c.GetRoleMembers(context.Background(), "role", "")
+	require.NoError(t, err)
+	require.Equal(t, "p2", page.NextPageToken)
+	require.Equal(t, 3, page.TotalRecords)
+	require.Len(t, page.Items, 2)
+
+	var ids []string
+	for user, err := range c.RoleMembers("role").All(context.Background()) {
+		require.NoError(t, err)
+		ids = append(ids, user.ID)
+	}
+	require.Equal(t, []string{"a", "b", "c"}, ids)
+
+	// Stopping early doesn't fetch the remaining pages.
+	requests := len(pageSizes)
+	for range c.RoleMembers("role").All(context.Background()) {
+		break
+	}
+	require.Len(t, pageSizes, requests+1)
+
+	for _, size := range pageSizes {
+		require.Equal(t, "300", size)
+	}
+}
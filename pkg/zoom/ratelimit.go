@@ -0,0 +1,180 @@
+package zoom
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitCategory is the rate limit class Zoom assigns to every API endpoint.
+type RateLimitCategory string
+
+const (
+	CategoryLight  RateLimitCategory = "light"
+	CategoryMedium RateLimitCategory = "medium"
+	CategoryHeavy  RateLimitCategory = "heavy"
+)
+
+// PlanTier is the Zoom account plan, which determines the per-second limits of each category.
+type PlanTier string
+
+const (
+	PlanPro        PlanTier = "pro"
+	PlanBusiness   PlanTier = "business"
+	PlanEnterprise PlanTier = "enterprise"
+)
+
+var PlanTiers = []string{
+	string(PlanPro),
+	string(PlanBusiness),
+	string(PlanEnterprise),
+}
+
+// RateLimits holds the allowed requests per second for each category.
+// A missing or zero entry leaves that category unthrottled.
+type RateLimits map[RateLimitCategory]int
+
+// DefaultRateLimits returns Zoom's published per-second limits for the plan.
+func DefaultRateLimits(plan PlanTier) (RateLimits, error) {
+	switch plan {
+	case PlanPro:
+		return RateLimits{
+			CategoryLight:  30,
+			CategoryMedium: 20,
+			CategoryHeavy:  10,
+		}, nil
+	// Zoom publishes the same limits for Business and every higher plan.
+	case PlanBusiness, PlanEnterprise:
+		return RateLimits{
+			CategoryLight:  80,
+			CategoryMedium: 60,
+			CategoryHeavy:  40,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown zoom plan %q", plan)
+	}
+}
+
+// ParseRateLimits parses overrides in the form "light=30,medium=20,heavy=10".
+func ParseRateLimits(value string) (RateLimits, error) {
+	limits := RateLimits{}
+
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, rate, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate limit %q, expected category=requests_per_second", pair)
+		}
+
+		category := RateLimitCategory(strings.ToLower(strings.TrimSpace(name)))
+		switch category {
+		case CategoryLight, CategoryMedium, CategoryHeavy:
+		default:
+			return nil, fmt.Errorf("unknown rate limit category %q", name)
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(rate))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid requests per second %q for category %s", rate, category)
+		}
+		limits[category] = n
+	}
+
+	return limits, nil
+}
+
+// Merge returns a copy of the limits with the overrides applied on top.
+func (r RateLimits) Merge(overrides RateLimits) RateLimits {
+	merged := RateLimits{}
+	for category, rate := range r {
+		merged[category] = rate
+	}
+	for category, rate := range overrides {
+		merged[category] = rate
+	}
+
+	return merged
+}
+
+// rateLimiter schedules requests so each category stays under its limit.
+type rateLimiter struct {
+	buckets map[RateLimitCategory]*tokenBucket
+}
+
+func newRateLimiter(limits RateLimits) *rateLimiter {
+	buckets := make(map[RateLimitCategory]*tokenBucket, len(limits))
+	for category, rate := range limits {
+		if rate > 0 {
+			buckets[category] = newTokenBucket(rate)
+		}
+	}
+
+	return &rateLimiter{buckets: buckets}
+}
+
+// wait blocks until a request of the given category may be sent.
+func (r *rateLimiter) wait(ctx context.Context, category RateLimitCategory) error {
+	if r == nil {
+		return nil
+	}
+
+	bucket, ok := r.buckets[category]
+	if !ok {
+		return nil
+	}
+
+	return bucket.wait(ctx)
+}
+
+// tokenBucket allows a burst of up to one second's worth of requests and refills continuously.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(perSecond int) *tokenBucket {
+	return &tokenBucket{
+		rate:   float64(perSecond),
+		tokens: float64(perSecond),
+		last:   time.Now(),
+	}
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		delay := b.reserve()
+		if delay == 0 {
+			return nil
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes a token and returns zero, or returns how long until one is available.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.rate, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+
+	return max(time.Duration((1-b.tokens)/b.rate*float64(time.Second)), time.Nanosecond)
+}
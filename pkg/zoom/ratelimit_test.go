@@ -0,0 +1,44 @@
+package zoom
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRateLimits(t *testing.T) {
+	limits, err := ParseRateLimits("light=20, Medium=10,heavy=0")
+	require.NoError(t, err)
+	require.Equal(t, RateLimits{CategoryLight: 20, CategoryMedium: 10, CategoryHeavy: 0}, limits)
+
+	defaults, err := DefaultRateLimits(PlanPro)
+	require.NoError(t, err)
+	require.Equal(t, RateLimits{CategoryLight: 20, CategoryMedium: 10, CategoryHeavy: 0}, defaults.Merge(limits))
+
+	_, err = ParseRateLimits("resource-intensive=1")
+	require.Error(t, err)
+
+	_, err = ParseRateLimits("light")
+	require.Error(t, err)
+}
+
+func TestRateLimiterThrottlesPerCategory(t *testing.T) {
+	limiter := newRateLimiter(RateLimits{CategoryMedium: 10})
+	ctx := context.Background()
+
+	start := time.Now()
+	// The first second's worth is a burst, the next request has to wait for a refill.
+	for i := 0; i < 11; i++ {
+		require.NoError(t, limiter.wait(ctx, CategoryMedium))
+	}
+	require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	// Categories without a limit are never held back.
+	start = time.Now()
+	for i := 0; i < 100; i++ {
+		require.NoError(t, limiter.wait(ctx, CategoryLight))
+	}
+	require.Less(t, time.Since(start), 50*time.Millisecond)
+}
@@ -0,0 +1,399 @@
+package zoom
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const redacted = "REDACTED"
+
+// Response headers worth keeping in a fixture, everything else is dropped.
+var recordedHeaders = []string{
+	"Content-Type",
+	rateLimitTypeHeader,
+	rateLimitRemainingHeader,
+	"X-RateLimit-Limit",
+	"X-RateLimit-Category",
+	retryAfterHeader,
+	trackingIdHeader,
+}
+
+// JSON fields holding tokens or personal data.
+var (
+	secretFields = map[string]bool{
+		"access_token":  true,
+		"refresh_token": true,
+	}
+	emailFields = map[string]bool{
+		"email":          true,
+		"transfer_email": true,
+	}
+	// Contact group members only carry a "name", so group and role names are pseudonymized as well.
+	nameFields = map[string]bool{
+		"first_name":         true,
+		"last_name":          true,
+		"display_name":       true,
+		"name":               true,
+		"group_name":         true,
+		"manager":            true,
+		"employee_unique_id": true,
+	}
+	// Personal data with no use in a fixture, dropped rather than pseudonymized.
+	droppedFields = map[string]bool{
+		"pmi":          true,
+		"phone_number": true,
+		"pic_url":      true,
+		"location":     true,
+	}
+	secretQueryParams = []string{"account_id"}
+)
+
+// customAttributesField holds key/name/value objects, of which only the value is personal.
+const customAttributesField = "custom_attributes"
+
+// interaction is one recorded request/response pair, stored as a JSON file.
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Query  string          `json:"query,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+type recordedResponse struct {
+	StatusCode int             `json:"status_code"`
+	Header     http.Header     `json:"header,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+}
+
+func (r recordedRequest) key() string {
+	return fmt.Sprintf("%s %s?%s", r.Method, r.Path, r.Query)
+}
+
+// RecordingTransport passes requests through to the base transport and saves
+// each request/response pair to a directory, with tokens, emails and names
+// replaced by stable placeholders. The directory can be served back with
+// ReplayTransport.
+type RecordingTransport struct {
+	base     http.RoundTripper
+	dir      string
+	redactor *redactor
+
+	mu  sync.Mutex
+	seq int
+}
+
+func NewRecordingTransport(base http.RoundTripper, dir string) (*RecordingTransport, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create record directory: %w", err)
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	r, err := newRedactor()
+	if err != nil {
+		return nil, err
+	}
+
+	return &RecordingTransport{base: base, dir: dir, redactor: r}, nil
+}
+
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		reqBody = b
+		req.Body = io.NopCloser(bytes.NewReader(b))
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	header := http.Header{}
+	for _, h := range recordedHeaders {
+		if v := resp.Header.Values(h); len(v) > 0 {
+			header[http.CanonicalHeaderKey(h)] = v
+		}
+	}
+
+	rec := interaction{
+		Request: recordedRequest{
+			Method: req.Method,
+			Path:   t.redactor.path(req.URL.Path),
+			Query:  t.redactor.query(req.URL.Query()),
+			Body:   t.redactor.json(reqBody),
+		},
+		Response: recordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       t.redactor.json(respBody),
+		},
+	}
+
+	if err := t.save(rec); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (t *RecordingTransport) save(rec interaction) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(rec); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.seq++
+	name := filepath.Join(t.dir, fmt.Sprintf("%05d.json", t.seq))
+
+	return os.WriteFile(name, buf.Bytes(), 0o600)
+}
+
+// ReplayTransport answers requests from a directory written by RecordingTransport,
+// without touching the network. Requests are matched on method, path and query;
+// repeated requests are served the recorded responses in order, the last one sticking.
+type ReplayTransport struct {
+	// redactor only lets placeholders through unchanged, replayed requests carry nothing else.
+	redactor *redactor
+
+	mu           sync.Mutex
+	interactions map[string][]recordedResponse
+}
+
+func NewReplayTransport(dir string) (*ReplayTransport, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no recorded interactions found in %s", dir)
+	}
+	sort.Strings(files)
+	r, err := newRedactor()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &ReplayTransport{redactor: r, interactions: map[string][]recordedResponse{}}
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		var rec interaction
+		if err := json.Unmarshal(b, &rec); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", f, err)
+		}
+
+		key := rec.Request.key()
+		t.interactions[key] = append(t.interactions[key], rec.Response)
+	}
+
+	return t, nil
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	key := recordedRequest{
+		Method: req.Method,
+		Path:   t.redactor.path(req.URL.Path),
+		Query:  t.redactor.query(req.URL.Query()),
+	}.key()
+
+	t.mu.Lock()
+	responses := t.interactions[key]
+	if len(responses) == 0 {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("zoom replay: no recorded response for %s", key)
+	}
+	rec := responses[0]
+	if len(responses) > 1 {
+		t.interactions[key] = responses[1:]
+	}
+	t.mu.Unlock()
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.StatusCode, http.StatusText(rec.StatusCode)),
+		StatusCode:    rec.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        rec.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(rec.Body)),
+		ContentLength: int64(len(rec.Body)),
+		Request:       req,
+	}, nil
+}
+
+// redactor replaces personal data with placeholders. Pseudonyms are keyed with a secret that only lives as long as
+// the recording, so they can't be reversed by hashing candidate emails or names.
+type redactor struct {
+	key []byte
+}
+
+func newRedactor() (*redactor, error) {
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate the redaction key: %w", err)
+	}
+
+	return &redactor{key: key}, nil
+}
+
+// pseudonym maps a value to a stable placeholder, so the same person is
+// redacted the same way across every file of a fixture. Placeholders map to
+// themselves, so replayed requests match what was recorded.
+func (r *redactor) pseudonym(value string) string {
+	if strings.HasPrefix(value, "redacted-") {
+		return value
+	}
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(value))
+	return "redacted-" + hex.EncodeToString(mac.Sum(nil)[:4])
+}
+
+func (r *redactor) email(email string) string {
+	if email == "" || strings.HasPrefix(email, "redacted-") {
+		return email
+	}
+	return r.pseudonym(strings.ToLower(email)) + "@example.com"
+}
+
+// path redacts emails used as user IDs, e.g. /users/jane@example.com.
+func (r *redactor) path(p string) string {
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		if unescaped, err := url.PathUnescape(s); err == nil && strings.Contains(unescaped, "@") {
+			segments[i] = r.email(unescaped)
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func (r *redactor) query(q url.Values) string {
+	for _, p := range secretQueryParams {
+		if q.Has(p) {
+			q.Set(p, redacted)
+		}
+	}
+	if q.Has("email") {
+		q.Set("email", r.email(q.Get("email")))
+	}
+
+	return q.Encode()
+}
+
+// json redacts a JSON body, non-JSON bodies are replaced by a placeholder.
+func (r *redactor) json(body []byte) json.RawMessage {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		b, _ := json.Marshal(redacted)
+		return b
+	}
+
+	b, err := json.Marshal(r.value("", v))
+	if err != nil {
+		return nil
+	}
+
+	return b
+}
+
+func (r *redactor) value(key string, v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			if key == customAttributesField && k == "value" {
+				val[k] = dropped(child)
+				continue
+			}
+			val[k] = r.value(k, child)
+		}
+		return val
+	case []any:
+		for i, child := range val {
+			val[i] = r.value(key, child)
+		}
+		return val
+	}
+
+	switch {
+	case droppedFields[key]:
+		return dropped(v)
+	case secretFields[key]:
+		if s, ok := v.(string); ok && s != "" {
+			return redacted
+		}
+	case emailFields[key]:
+		if s, ok := v.(string); ok {
+			return r.email(s)
+		}
+	case nameFields[key]:
+		if s, ok := v.(string); ok && s != "" {
+			return r.pseudonym(s)
+		}
+	}
+
+	return v
+}
+
+// dropped replaces a value by a placeholder of the same JSON type, so it still decodes into the same field.
+func dropped(v any) any {
+	switch v.(type) {
+	case string:
+		return redacted
+	case float64:
+		return 0
+	case bool:
+		return false
+	case nil:
+		return nil
+	}
+
+	return redacted
+}
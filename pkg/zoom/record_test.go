@@ -0,0 +1,118 @@
+package zoom_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conductorone/baton-zoom/pkg/zoom"
+	"github.com/conductorone/baton-zoom/pkg/zoom/zoomtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	srv := zoomtest.NewServer()
+	defer srv.Close()
+
+	jane := srv.AddUser(zoom.User{Email: "jane.doe@example.org", FirstName: "Jane", LastName: "Doe", DisplayName: "Jane Doe"})
+	group := srv.AddGroup(zoom.Group{Name: "Engineering"})
+	srv.AddGroupMember(group.ID, jane.ID)
+
+	dir := t.TempDir()
+	recorder, err := zoom.NewRecordingTransport(srv.Client().Transport, dir)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	endpoints := srv.Endpoints()
+	recordClient := &http.Client{Transport: recorder}
+	c := zoom.NewClient(recordClient, endpoints.APIBaseURL,
+		zoom.NewTokenSource(recordClient, endpoints.OAuthURL, zoomtest.AccountID, zoomtest.ClientID, zoomtest.ClientSecret))
+
+	recorded, err := c.GetGroupMembers(ctx, group.ID)
+	require.NoError(t, err)
+	require.Len(t, recorded, 1)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		require.NoError(t, err)
+		for _, secret := range []string{"jane.doe@example.org", "Jane", "Doe", zoomtest.AccountID, "token-"} {
+			require.False(t, strings.Contains(string(b), secret), "%s leaks %q", f, secret)
+		}
+	}
+
+	// Replay from another "environment" without the fake server.
+	replay, err := zoom.NewReplayTransport(dir)
+	require.NoError(t, err)
+
+	replayClient := &http.Client{Transport: replay}
+	offline := zoom.NewClient(replayClient, "https://api.zoom.invalid/v2",
+		zoom.NewTokenSource(replayClient, "https://zoom.invalid/oauth/token", "other-account", "id", "secret"))
+
+	replayed, err := offline.GetGroupMembers(ctx, group.ID)
+	require.NoError(t, err)
+	require.Len(t, replayed, 1)
+	require.Equal(t, jane.ID, replayed[0].ID)
+	require.NotEqual(t, jane.Email, replayed[0].Email)
+	require.True(t, strings.HasSuffix(replayed[0].Email, "@example.com"))
+
+	_, err = offline.GetGroupMembers(ctx, "unrecorded")
+	require.ErrorContains(t, err, "no recorded response")
+}
+
+func TestRecordingDropsPersonalData(t *testing.T) {
+	srv := zoomtest.NewServer()
+	defer srv.Close()
+
+	jane := srv.AddUser(zoom.User{
+		Email:            "jane.doe@example.org",
+		DisplayName:      "Jane Doe",
+		PMI:              1234567890,
+		Location:         "Berlin",
+		CustomAttributes: []zoom.CustomAttribute{{Key: "cbf_cost_center", Name: "Cost Center", Value: "R&D-4711"}},
+	})
+
+	// Two recordings of the same user, the pseudonyms are keyed per recording.
+	var emails []string
+	for i := 0; i < 2; i++ {
+		dir := t.TempDir()
+		recorder, err := zoom.NewRecordingTransport(srv.Client().Transport, dir)
+		require.NoError(t, err)
+
+		endpoints := srv.Endpoints()
+		recordClient := &http.Client{Transport: recorder}
+		c := zoom.NewClient(recordClient, endpoints.APIBaseURL,
+			zoom.NewTokenSource(recordClient, endpoints.OAuthURL, zoomtest.AccountID, zoomtest.ClientID, zoomtest.ClientSecret))
+
+		_, _, err = c.GetUser(context.Background(), jane.ID)
+		require.NoError(t, err)
+
+		files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		require.NoError(t, err)
+		for _, f := range files {
+			b, err := os.ReadFile(f)
+			require.NoError(t, err)
+			for _, secret := range []string{"1234567890", "Berlin", "R&D-4711"} {
+				require.False(t, strings.Contains(string(b), secret), "%s leaks %q", f, secret)
+			}
+		}
+
+		replay, err := zoom.NewReplayTransport(dir)
+		require.NoError(t, err)
+		replayClient := &http.Client{Transport: replay}
+		offline := zoom.NewClient(replayClient, "https://api.zoom.invalid/v2",
+			zoom.NewTokenSource(replayClient, "https://zoom.invalid/oauth/token", "other-account", "id", "secret"))
+
+		replayed, _, err := offline.GetUser(context.Background(), jane.ID)
+		require.NoError(t, err)
+		require.Zero(t, replayed.PMI)
+		require.Equal(t, "cbf_cost_center", replayed.CustomAttributes[0].Key)
+		emails = append(emails, replayed.Email)
+	}
+	require.NotEqual(t, emails[0], emails[1])
+}
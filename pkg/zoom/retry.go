@@ -0,0 +1,103 @@
+package zoom
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	rateLimitTypeHeader      = "X-RateLimit-Type"
+	rateLimitRemainingHeader = "X-RateLimit-Remaining"
+	retryAfterHeader         = "Retry-After"
+
+	// X-RateLimit-Type is either "QPS" for the per-second limits or this for the daily quota.
+	rateLimitTypeDaily = "Daily-limit"
+)
+
+// RetryPolicy controls how the client retries rate limited (429) and server (5xx) errors.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt, zero disables retries.
+	MaxRetries int
+	// BaseDelay is the first backoff delay when Zoom doesn't send Retry-After, doubled on every retry.
+	BaseDelay time.Duration
+	// MaxDelay caps a single wait. A Retry-After longer than this is returned as an error instead of waited on.
+	MaxDelay time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  time.Second,
+		MaxDelay:   time.Minute,
+	}
+}
+
+func shouldRetry(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// retryDelay returns how long to wait before the given retry attempt (starting at 0),
+// and false if the response should not be retried at all.
+func (p RetryPolicy) retryDelay(resp *http.Response, attempt int) (time.Duration, bool) {
+	if attempt >= p.MaxRetries || !shouldRetry(resp.StatusCode) {
+		return 0, false
+	}
+
+	// The daily quota won't come back for hours, there's no point in spinning on it.
+	if isDailyLimit(resp) {
+		return 0, false
+	}
+
+	delay := p.BaseDelay << attempt
+	if retryAfter, ok := parseRetryAfter(resp.Header.Get(retryAfterHeader)); ok {
+		if retryAfter > p.MaxDelay {
+			return 0, false
+		}
+		delay = retryAfter
+	} else if resp.StatusCode == http.StatusTooManyRequests &&
+		resp.Header.Get(rateLimitRemainingHeader) == "0" &&
+		delay < time.Second {
+		// The per-second quota is used up, it refills on the next second.
+		delay = time.Second
+	}
+
+	return min(delay, p.MaxDelay), true
+}
+
+func isDailyLimit(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusTooManyRequests && resp.Header.Get(rateLimitTypeHeader) == rateLimitTypeDaily
+}
+
+// parseRetryAfter accepts both forms of Retry-After: delay seconds and an HTTP date.
+// Zoom also sends an RFC 3339 timestamp when the daily limit resets.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	for _, layout := range []string{http.TimeFormat, time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return max(time.Until(t), 0), true
+		}
+	}
+
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,97 @@
+package zoom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDoRequestRetriesRateLimited(t *testing.T) {
+	var calls atomic.Int32
+	tokenSrv, _ := newTokenServer(t, 3600)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set(rateLimitTypeHeader, "QPS")
+			w.Header().Set(retryAfterHeader, "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"code":429,"message":"You have reached the maximum per-second rate limit for this API."}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"me"}`)
+	}))
+	defer api.Close()
+
+	c := NewClient(api.Client(), api.URL, newTestTokenSource(tokenSrv), WithRetryPolicy(RetryPolicy{
+		MaxRetries: 2,
+		BaseDelay:  time.Millisecond,
+		MaxDelay:   time.Second,
+	}))
+
+	var user User
+	_, err := c.doRequest(context.Background(), CategoryLight, api.URL, &user, http.MethodGet, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, "me", user.ID)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func TestRetryDelay(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 10 * time.Second}
+
+	tests := []struct {
+		name    string
+		status  int
+		headers map[string]string
+		attempt int
+		want    time.Duration
+		retry   bool
+	}{
+		{"server error backs off", http.StatusBadGateway, nil, 2, 400 * time.Millisecond, true},
+		{"retry after is honored", http.StatusTooManyRequests, map[string]string{retryAfterHeader: "2"}, 0, 2 * time.Second, true},
+		{"exhausted qps waits for the next second", http.StatusTooManyRequests, map[string]string{rateLimitRemainingHeader: "0"}, 0, time.Second, true},
+		{"daily limit is not retried", http.StatusTooManyRequests, map[string]string{rateLimitTypeHeader: rateLimitTypeDaily}, 0, 0, false},
+		{"long retry after is not waited on", http.StatusTooManyRequests, map[string]string{retryAfterHeader: "3600"}, 0, 0, false},
+		{"client errors are not retried", http.StatusBadRequest, nil, 0, 0, false},
+		{"retries run out", http.StatusServiceUnavailable, nil, 3, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			for k, v := range tt.headers {
+				resp.Header.Set(k, v)
+			}
+
+			got, ok := policy.retryDelay(resp, tt.attempt)
+			require.Equal(t, tt.retry, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDoRequestDailyLimit(t *testing.T) {
+	var calls atomic.Int32
+	tokenSrv, _ := newTokenServer(t, 3600)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set(rateLimitTypeHeader, rateLimitTypeDaily)
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"code":429,"message":"You have reached the maximum daily rate limit for this API."}`)
+	}))
+	defer api.Close()
+
+	c := NewClient(api.Client(), api.URL, newTestTokenSource(tokenSrv))
+
+	_, err := c.doRequest(context.Background(), CategoryLight, api.URL, nil, http.MethodGet, nil, nil)
+	require.True(t, errors.Is(err, ErrDailyLimitExceeded))
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.Equal(t, int32(1), calls.Load())
+}
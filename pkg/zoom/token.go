@@ -0,0 +1,123 @@
+package zoom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// tokenExpiryDelta is how long before the reported expiry a token is refreshed,
+// so requests already in flight don't race the expiration.
+const tokenExpiryDelta = 5 * time.Minute
+
+type accessToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
+
+// TokenSource provides Server-to-Server OAuth tokens for the Zoom API and
+// refreshes them before they expire. It is safe for concurrent use.
+type TokenSource struct {
+	httpClient   *http.Client
+	authUrl      string
+	accountId    string
+	clientId     string
+	clientSecret string
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewTokenSource creates a token source for the OAuth token endpoint at authUrl, e.g. Endpoints.OAuthURL.
+func NewTokenSource(httpClient *http.Client, authUrl, accountId, clientId, clientSecret string) *TokenSource {
+	return &TokenSource{
+		httpClient:   httpClient,
+		authUrl:      authUrl,
+		accountId:    accountId,
+		clientId:     clientId,
+		clientSecret: clientSecret,
+	}
+}
+
+// Token returns a valid access token, requesting a new one if the current token
+// is missing or about to expire.
+func (t *TokenSource) Token(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != "" && time.Now().Before(t.expiresAt) {
+		return t.token, nil
+	}
+
+	res, err := requestAccessToken(ctx, t.httpClient, t.authUrl, t.accountId, t.clientId, t.clientSecret)
+	if err != nil {
+		return "", err
+	}
+
+	t.token = res.AccessToken
+	t.expiresAt = time.Now().Add(time.Duration(res.ExpiresIn)*time.Second - tokenExpiryDelta)
+
+	return t.token, nil
+}
+
+// Invalidate drops the cached token if it is still the given one, forcing the next
+// call to Token to re-authenticate. Passing the rejected token keeps concurrent
+// callers that hit the same 401 from refreshing more than once.
+func (t *TokenSource) Invalidate(token string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token == token {
+		t.token = ""
+		t.expiresAt = time.Time{}
+	}
+}
+
+func requestAccessToken(ctx context.Context, httpClient *http.Client, authUrl, accountId, clientId, clientSecret string) (*accessToken, error) {
+	data := url.Values{}
+	data.Add("account_id", accountId)
+	data.Add("grant_type", "account_credentials")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, authUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("accept", "application/json")
+	req.SetBasicAuth(clientId, clientSecret)
+	req.URL.RawQuery = data.Encode()
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("token request failed: %w", newAPIError(resp, b))
+	}
+
+	var res accessToken
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+
+	if res.AccessToken == "" {
+		return nil, fmt.Errorf("token response did not contain an access token")
+	}
+
+	return &res, nil
+}
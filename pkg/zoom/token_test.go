@@ -0,0 +1,86 @@
+package zoom
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTokenServer(t *testing.T, expiresIn int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var issued atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := issued.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":%d}`, n, expiresIn)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &issued
+}
+
+func newTestTokenSource(srv *httptest.Server) *TokenSource {
+	return NewTokenSource(srv.Client(), srv.URL, "account", "client", "secret")
+}
+
+func TestTokenSourceCachesToken(t *testing.T) {
+	srv, issued := newTokenServer(t, 3600)
+	ts := newTestTokenSource(srv)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := ts.Token(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, "token-1", token)
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(1), issued.Load())
+}
+
+func TestTokenSourceRefreshesBeforeExpiry(t *testing.T) {
+	// Anything shorter than tokenExpiryDelta is treated as already expired.
+	srv, issued := newTokenServer(t, 60)
+	ts := newTestTokenSource(srv)
+
+	first, err := ts.Token(context.Background())
+	require.NoError(t, err)
+	second, err := ts.Token(context.Background())
+	require.NoError(t, err)
+
+	require.NotEqual(t, first, second)
+	require.Equal(t, int32(2), issued.Load())
+}
+
+func TestClientReauthenticatesOnUnauthorized(t *testing.T) {
+	tokenSrv, issued := newTokenServer(t, 3600)
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"code":124,"message":"Invalid access token."}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"me","email":"owner@example.com"}`)
+	}))
+	defer api.Close()
+
+	c := NewClient(api.Client(), api.URL, newTestTokenSource(tokenSrv))
+
+	var user User
+	_, err := c.doRequest(context.Background(), CategoryLight, api.URL, &user, http.MethodGet, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, "owner@example.com", user.Email)
+	require.Equal(t, int32(2), issued.Load())
+}
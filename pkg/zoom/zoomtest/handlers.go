@@ -0,0 +1,735 @@
+package zoomtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/conductorone/baton-zoom/pkg/zoom"
+)
+
+func (s *Server) findUser(userId string) *zoom.User {
+	for _, u := range s.users {
+		switch {
+		case userId == "me" && u.RoleID == OwnerRoleID,
+			u.ID == userId,
+			strings.EqualFold(u.Email, userId):
+			return u
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) findGroup(groupId string) *zoom.Group {
+	for i := range s.groups {
+		if s.groups[i].ID == groupId {
+			return &s.groups[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) findRole(roleId string) *zoom.Role {
+	for i := range s.roles {
+		if s.roles[i].ID == roleId {
+			return &s.roles[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) usersByID(ids []string) []zoom.User {
+	users := make([]zoom.User, 0, len(ids))
+	for _, id := range ids {
+		if u := s.findUser(id); u != nil {
+			users = append(users, *u)
+		}
+	}
+
+	return users
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Like Zoom, only active users are listed unless another status is asked for.
+	userStatus := r.URL.Query().Get("status")
+	if userStatus == "" {
+		userStatus = zoom.UserStatusActive
+	}
+
+	customAttributes := slices.Contains(strings.Split(r.URL.Query().Get("include_fields"), ","), "custom_attributes")
+
+	var users []zoom.User
+	for _, u := range s.users {
+		if u.Status == userStatus {
+			listed := listedUser(*u)
+			if customAttributes {
+				listed.CustomAttributes = u.CustomAttributes
+			}
+			users = append(users, listed)
+		}
+	}
+
+	paginate(w, r, "users", users)
+}
+
+// listedUser drops the fields Zoom only returns when getting a single user, or for custom attributes when asked for
+// with include_fields.
+func listedUser(u zoom.User) zoom.User {
+	u.JobTitle = ""
+	u.Manager = ""
+	u.Location = ""
+	u.LoginTypes = nil
+	u.CustomAttributes = nil
+
+	return u
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId := r.PathValue("userId")
+	u := s.findUser(userId)
+	if u == nil {
+		writeError(w, http.StatusNotFound, codeUserNotFound, fmt.Sprintf("User does not exist: %s.", userId))
+		return
+	}
+
+	user := *u
+	user.GroupIDs = nil
+	for _, g := range s.groups {
+		if slices.Contains(s.groupMembers[g.ID], u.ID) {
+			user.GroupIDs = append(user.GroupIDs, g.ID)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request) {
+	var body zoom.UserCreationBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.UserInfo.Email == "" {
+		writeError(w, http.StatusBadRequest, 300, "Validation Failed.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.findUser(body.UserInfo.Email) != nil {
+		writeError(w, http.StatusConflict, codeUserAlreadyExists, fmt.Sprintf("User already in the account: %s", body.UserInfo.Email))
+		return
+	}
+	if body.UserInfo.Type == zoom.LicensedUser && !s.hasSeat(LicensedSeats, func(other *zoom.User) bool { return other.Type == int(zoom.LicensedUser) }) {
+		writeError(w, http.StatusBadRequest, codeNotEnoughLicenses, "Your account doesn't have enough licenses.")
+		return
+	}
+
+	userStatus := "active"
+	if body.Action == zoom.CreateUser {
+		// The user has to confirm the invitation email first.
+		userStatus = "pending"
+	}
+
+	u := &zoom.User{
+		ID:          s.newId("user"),
+		Email:       body.UserInfo.Email,
+		FirstName:   body.UserInfo.FirstName,
+		LastName:    body.UserInfo.LastName,
+		DisplayName: body.UserInfo.DisplayName,
+		Type:        int(body.UserInfo.Type),
+		RoleID:      MemberRoleID,
+		RoleName:    "Member",
+		Status:      userStatus,
+	}
+	s.users = append(s.users, u)
+
+	writeJSON(w, http.StatusCreated, zoom.UserCreationResponse{
+		Id:        u.ID,
+		Email:     u.Email,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Type:      u.Type,
+	})
+}
+
+func (s *Server) updateUser(w http.ResponseWriter, r *http.Request) {
+	var body zoom.UserUpdateBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, 300, "Validation Failed.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId := r.PathValue("userId")
+	u := s.findUser(userId)
+	if u == nil {
+		writeError(w, http.StatusNotFound, codeUserNotFound, fmt.Sprintf("User does not exist: %s.", userId))
+		return
+	}
+
+	if body.Type != 0 && int(body.Type) != u.Type {
+		if body.Type == zoom.LicensedUser && !s.hasSeat(LicensedSeats, func(other *zoom.User) bool { return other.Type == int(zoom.LicensedUser) }) {
+			writeError(w, http.StatusBadRequest, codeNotEnoughLicenses, "Your account doesn't have enough licenses.")
+			return
+		}
+		u.Type = int(body.Type)
+	}
+	if body.Dept != "" {
+		u.Department = body.Dept
+	}
+	if body.JobTitle != "" {
+		u.JobTitle = body.JobTitle
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// hasSeat reports whether another user can get a license, holds counts the users that have it.
+func (s *Server) hasSeat(license string, holds func(*zoom.User) bool) bool {
+	seats, ok := s.seats[license]
+	if !ok {
+		return true
+	}
+
+	used := 0
+	for _, u := range s.users {
+		if holds(u) {
+			used++
+		}
+	}
+
+	return used < seats
+}
+
+func (s *Server) getUserSettings(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId := r.PathValue("userId")
+	u := s.findUser(userId)
+	if u == nil {
+		writeError(w, http.StatusNotFound, codeUserNotFound, fmt.Sprintf("User does not exist: %s.", userId))
+		return
+	}
+
+	settings := zoom.UserSettings{}
+	if f := s.features[u.ID]; f != nil {
+		settings.Feature = *f
+	}
+
+	writeJSON(w, http.StatusOK, settings)
+}
+
+func (s *Server) updateUserSettings(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Feature map[string]json.RawMessage `json:"feature"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, 300, "Validation Failed.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId := r.PathValue("userId")
+	u := s.findUser(userId)
+	if u == nil {
+		writeError(w, http.StatusNotFound, codeUserNotFound, fmt.Sprintf("User does not exist: %s.", userId))
+		return
+	}
+
+	current := zoom.UserFeatures{}
+	if f := s.features[u.ID]; f != nil {
+		current = *f
+	}
+
+	// Merge the changed flags into the current ones by round-tripping through JSON.
+	b, _ := json.Marshal(current)
+	merged := map[string]json.RawMessage{}
+	_ = json.Unmarshal(b, &merged)
+
+	for feature, value := range body.Feature {
+		if string(value) == "true" && string(merged[feature]) != "true" {
+			if !s.hasSeat(feature, func(other *zoom.User) bool { return s.hasFeature(other.ID, feature) }) {
+				writeError(w, http.StatusBadRequest, codeNotEnoughLicenses, fmt.Sprintf("Your account doesn't have enough %s licenses.", feature))
+				return
+			}
+		}
+		merged[feature] = value
+	}
+
+	b, _ = json.Marshal(merged)
+	updated := zoom.UserFeatures{}
+	if err := json.Unmarshal(b, &updated); err != nil {
+		writeError(w, http.StatusBadRequest, 300, "Validation Failed.")
+		return
+	}
+	s.features[u.ID] = &updated
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) hasFeature(userId string, feature string) bool {
+	f := s.features[userId]
+	if f == nil {
+		return false
+	}
+
+	b, _ := json.Marshal(f)
+	flags := map[string]json.RawMessage{}
+	_ = json.Unmarshal(b, &flags)
+
+	return string(flags[feature]) == "true"
+}
+
+func (s *Server) updateUserStatus(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Action string `json:"action"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, 300, "Validation Failed.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId := r.PathValue("userId")
+	u := s.findUser(userId)
+	if u == nil {
+		writeError(w, http.StatusNotFound, codeUserNotFound, fmt.Sprintf("User does not exist: %s.", userId))
+		return
+	}
+
+	switch body.Action {
+	case zoom.ActivateUser:
+		u.Status = zoom.UserStatusActive
+	case zoom.DeactivateUser:
+		u.Status = zoom.UserStatusInactive
+	default:
+		writeError(w, http.StatusBadRequest, 300, "Invalid action.")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) revokeSSOToken(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId := r.PathValue("userId")
+	if s.findUser(userId) == nil {
+		writeError(w, http.StatusNotFound, codeUserNotFound, fmt.Sprintf("User does not exist: %s.", userId))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId := r.PathValue("userId")
+	u := s.findUser(userId)
+	if u == nil {
+		writeError(w, http.StatusNotFound, codeUserNotFound, fmt.Sprintf("User does not exist: %s.", userId))
+		return
+	}
+
+	q := r.URL.Query()
+	switch zoom.UserDeleteAction(q.Get("action")) {
+	case "", zoom.DisassociateUser, zoom.DeleteUser:
+	default:
+		writeError(w, http.StatusBadRequest, 300, "Invalid action.")
+		return
+	}
+	if transferEmail := q.Get("transfer_email"); transferEmail != "" {
+		if to := s.findUser(transferEmail); to == nil || to == u {
+			writeError(w, http.StatusBadRequest, codeUserNotFound, fmt.Sprintf("User does not exist: %s.", transferEmail))
+			return
+		}
+	}
+
+	s.users = slices.DeleteFunc(s.users, func(other *zoom.User) bool { return other == u })
+	delete(s.features, u.ID)
+	for _, memberships := range []map[string][]string{s.groupMembers, s.groupAdmins, s.roleMembers} {
+		for groupId, ids := range memberships {
+			memberships[groupId] = slices.DeleteFunc(ids, func(id string) bool { return id == u.ID })
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listGroups(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paginate(w, r, "groups", s.groups)
+}
+
+func (s *Server) listGroupMembers(memberships map[string][]string, key string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		groupId := r.PathValue("groupId")
+		if s.findGroup(groupId) == nil {
+			writeError(w, http.StatusNotFound, codeGroupNotFound, fmt.Sprintf("Group does not exist: %s.", groupId))
+			return
+		}
+
+		paginate(w, r, key, s.usersByID(memberships[groupId]))
+	}
+}
+
+func (s *Server) addGroupMembers(memberships map[string][]string, key string, existsCode int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body map[string][]zoom.Payload
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body[key]) == 0 {
+			writeError(w, http.StatusBadRequest, 300, "Validation Failed.")
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		groupId := r.PathValue("groupId")
+		if s.findGroup(groupId) == nil {
+			writeError(w, http.StatusNotFound, codeGroupNotFound, fmt.Sprintf("Group does not exist: %s.", groupId))
+			return
+		}
+
+		var ids []string
+		for _, m := range body[key] {
+			u := s.findUser(m.ID)
+			if u == nil {
+				writeError(w, http.StatusNotFound, codeUserNotFound, fmt.Sprintf("User does not exist: %s.", m.ID))
+				return
+			}
+			if slices.Contains(memberships[groupId], u.ID) {
+				writeError(w, http.StatusBadRequest, existsCode, fmt.Sprintf("User is already in the group: %s.", m.ID))
+				return
+			}
+			memberships[groupId] = append(memberships[groupId], u.ID)
+			ids = append(ids, u.ID)
+		}
+
+		writeJSON(w, http.StatusCreated, map[string]string{
+			"ids":      strings.Join(ids, ","),
+			"added_at": time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+}
+
+func (s *Server) deleteGroupMember(memberships map[string][]string, notFoundCode int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		groupId := r.PathValue("groupId")
+		userId := r.PathValue("userId")
+		if s.findGroup(groupId) == nil {
+			writeError(w, http.StatusNotFound, codeGroupNotFound, fmt.Sprintf("Group does not exist: %s.", groupId))
+			return
+		}
+
+		if s.findUser(userId) == nil {
+			writeError(w, http.StatusNotFound, codeUserNotFound, fmt.Sprintf("User does not exist: %s.", userId))
+			return
+		}
+		if !slices.Contains(memberships[groupId], userId) {
+			writeError(w, http.StatusNotFound, notFoundCode, fmt.Sprintf("User is not in the group: %s.", userId))
+			return
+		}
+
+		memberships[groupId] = slices.DeleteFunc(memberships[groupId], func(id string) bool { return id == userId })
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) listRoles(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Like Zoom, only common roles are listed unless another type is asked for.
+	roleType := r.URL.Query().Get("type")
+	if roleType == "" {
+		roleType = "common"
+	}
+
+	var roles []zoom.Role
+	for _, role := range s.roles {
+		if role.Type == roleType {
+			// Like Zoom, privileges are only returned for a single role.
+			role.Privileges = nil
+			role.TotalMembers = s.countRoleMembers(role.ID)
+			roles = append(roles, role)
+		}
+	}
+
+	paginate(w, r, "roles", roles)
+}
+
+func isCommonRole(role *zoom.Role) bool {
+	return role.Type == zoom.RoleTypeCommon
+}
+
+func (s *Server) roleMemberUsers(role *zoom.Role) []zoom.User {
+	if !isCommonRole(role) {
+		return s.usersByID(s.roleMembers[role.ID])
+	}
+
+	var members []zoom.User
+	for _, u := range s.users {
+		if u.RoleID == role.ID {
+			members = append(members, *u)
+		}
+	}
+
+	return members
+}
+
+func (s *Server) countRoleMembers(roleId string) int {
+	return len(s.roleMemberUsers(s.findRole(roleId)))
+}
+
+func (s *Server) getRole(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	roleId := r.PathValue("roleId")
+	role := s.findRole(roleId)
+	if role == nil {
+		writeError(w, http.StatusNotFound, 1001, fmt.Sprintf("Role does not exist: %s.", roleId))
+		return
+	}
+
+	res := *role
+	res.TotalMembers = s.countRoleMembers(role.ID)
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) createRole(w http.ResponseWriter, r *http.Request) {
+	var body zoom.RoleBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Name == "" {
+		writeError(w, http.StatusBadRequest, 300, "Validation Failed.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, role := range s.roles {
+		if strings.EqualFold(role.Name, body.Name) {
+			writeError(w, http.StatusConflict, 4700, fmt.Sprintf("Role name already exists: %s.", body.Name))
+			return
+		}
+	}
+
+	role := zoom.Role{
+		ID:          s.newId("role"),
+		Name:        body.Name,
+		Description: body.Description,
+		Type:        body.Type,
+		Privileges:  body.Privileges,
+	}
+	if role.Type == "" {
+		role.Type = zoom.RoleTypeCommon
+	}
+	s.roles = append(s.roles, role)
+
+	writeJSON(w, http.StatusCreated, role)
+}
+
+func (s *Server) updateRole(w http.ResponseWriter, r *http.Request) {
+	var body zoom.RoleBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, 300, "Validation Failed.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	roleId := r.PathValue("roleId")
+	role := s.findRole(roleId)
+	if role == nil {
+		writeError(w, http.StatusNotFound, 1001, fmt.Sprintf("Role does not exist: %s.", roleId))
+		return
+	}
+	if zoom.IsBuiltInRole(roleId) {
+		writeError(w, http.StatusBadRequest, 300, "Built-in roles can't be changed.")
+		return
+	}
+
+	if body.Name != "" {
+		role.Name = body.Name
+	}
+	if body.Description != "" {
+		role.Description = body.Description
+	}
+	if body.Privileges != nil {
+		role.Privileges = body.Privileges
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteRole(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	roleId := r.PathValue("roleId")
+	role := s.findRole(roleId)
+	if role == nil {
+		writeError(w, http.StatusNotFound, 1001, fmt.Sprintf("Role does not exist: %s.", roleId))
+		return
+	}
+	if zoom.IsBuiltInRole(roleId) {
+		writeError(w, http.StatusBadRequest, 300, "Built-in roles can't be deleted.")
+		return
+	}
+	if s.countRoleMembers(roleId) > 0 {
+		writeError(w, http.StatusBadRequest, 300, "Role still has members.")
+		return
+	}
+
+	s.roles = slices.DeleteFunc(s.roles, func(other zoom.Role) bool { return other.ID == roleId })
+	delete(s.roleMembers, roleId)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listRoleMembers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	roleId := r.PathValue("roleId")
+	role := s.findRole(roleId)
+	if role == nil {
+		writeError(w, http.StatusNotFound, 1001, fmt.Sprintf("Role does not exist: %s.", roleId))
+		return
+	}
+
+	paginate(w, r, "members", s.roleMemberUsers(role))
+}
+
+func (s *Server) assignRole(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Members []zoom.Payload `json:"members"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Members) == 0 {
+		writeError(w, http.StatusBadRequest, 300, "Validation Failed.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	roleId := r.PathValue("roleId")
+	role := s.findRole(roleId)
+	if role == nil {
+		writeError(w, http.StatusNotFound, 1001, fmt.Sprintf("Role does not exist: %s.", roleId))
+		return
+	}
+
+	var ids []string
+	for _, m := range body.Members {
+		u := s.findUser(m.ID)
+		if u == nil {
+			writeError(w, http.StatusNotFound, codeUserNotFound, fmt.Sprintf("User does not exist: %s.", m.ID))
+			return
+		}
+		if !isCommonRole(role) {
+			if slices.Contains(s.roleMembers[role.ID], u.ID) {
+				writeError(w, http.StatusBadRequest, codeRoleMemberExists, fmt.Sprintf("User already has the role: %s.", m.ID))
+				return
+			}
+			s.roleMembers[role.ID] = append(s.roleMembers[role.ID], u.ID)
+			ids = append(ids, u.ID)
+			continue
+		}
+		if u.RoleID == role.ID {
+			writeError(w, http.StatusBadRequest, codeRoleMemberExists, fmt.Sprintf("User already has the role: %s.", m.ID))
+			return
+		}
+		// A user has exactly one common role, assigning a new one replaces the old one.
+		u.RoleID = role.ID
+		u.RoleName = role.Name
+		ids = append(ids, u.ID)
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]string{
+		"ids":    strings.Join(ids, ","),
+		"add_at": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+func (s *Server) unassignRole(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	roleId := r.PathValue("roleId")
+	userId := r.PathValue("userId")
+	role := s.findRole(roleId)
+	if role == nil {
+		writeError(w, http.StatusNotFound, 1001, fmt.Sprintf("Role does not exist: %s.", roleId))
+		return
+	}
+
+	u := s.findUser(userId)
+	if u == nil {
+		writeError(w, http.StatusNotFound, codeUserNotFound, fmt.Sprintf("User does not exist: %s.", userId))
+		return
+	}
+	if !isCommonRole(role) {
+		if !slices.Contains(s.roleMembers[roleId], u.ID) {
+			writeError(w, http.StatusNotFound, codeRoleMemberNotFound, fmt.Sprintf("User does not have the role: %s.", userId))
+			return
+		}
+		s.roleMembers[roleId] = slices.DeleteFunc(s.roleMembers[roleId], func(id string) bool { return id == u.ID })
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if u.RoleID != roleId {
+		writeError(w, http.StatusNotFound, codeRoleMemberNotFound, fmt.Sprintf("User does not have the role: %s.", userId))
+		return
+	}
+
+	// Unassigned users fall back to the Member role.
+	u.RoleID = MemberRoleID
+	u.RoleName = "Member"
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listContactGroups(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paginate(w, r, "groups", s.contactGroups)
+}
+
+func (s *Server) listContactGroupMembers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paginate(w, r, "group_members", s.contactGroupMembers[r.PathValue("groupId")])
+}
@@ -0,0 +1,463 @@
+// Package zoomtest provides an in-memory fake of the Zoom API endpoints used by the connector.
+package zoomtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/conductorone/baton-zoom/pkg/zoom"
+)
+
+// Credentials accepted by the fake OAuth endpoint.
+const (
+	AccountID    = "test-account"
+	ClientID     = "test-client"
+	ClientSecret = "test-secret"
+)
+
+// Zoom's built-in roles.
+const (
+	OwnerRoleID  = zoom.OwnerRoleID
+	AdminRoleID  = zoom.AdminRoleID
+	MemberRoleID = zoom.MemberRoleID
+)
+
+// Zoom error codes answered by the fake, written out rather than taken from package zoom so that a wrong
+// constant there fails the tests instead of being mirrored here.
+const (
+	codeUserNotFound        = 1001
+	codeUserAlreadyExists   = 1005
+	codeNotEnoughLicenses   = 2034
+	codeGroupNotFound       = 4130
+	codeGroupMemberExists   = 4132
+	codeGroupAdminExists    = 4133
+	codeGroupMemberNotFound = 4136
+	codeGroupAdminNotFound  = 4137
+	codeRoleMemberExists    = 4140
+	codeRoleMemberNotFound  = 4141
+)
+
+const (
+	defaultPageSize = 30
+	tokenTTL        = time.Hour
+)
+
+type failure struct {
+	status  int
+	code    int
+	message string
+	header  http.Header
+}
+
+// Server is a fake Zoom API backed by in-memory state. It paginates with
+// next_page_token, answers errors with Zoom's {code, message} bodies and can
+// be told to fail upcoming requests, e.g. with 429s.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	nextId   int
+	tokens   map[string]time.Time
+	failures []failure
+	requests []string
+
+	users        []*zoom.User
+	groups       []zoom.Group
+	groupMembers map[string][]string
+	groupAdmins  map[string][]string
+	roles        []zoom.Role
+	// roleMembers holds the members of IQ and phone roles, common roles are the user's RoleID.
+	roleMembers         map[string][]string
+	contactGroups       []zoom.ContactGroup
+	contactGroupMembers map[string][]zoom.GroupMember
+	features            map[string]*zoom.UserFeatures
+	// seats limits the Licensed users and add-ons of the account, keyed by LicensedSeats or feature name.
+	// Licenses without an entry are unlimited.
+	seats map[string]int
+}
+
+// LicensedSeats is the SetSeats key of the Licensed user type.
+const LicensedSeats = "licensed"
+
+// NewServer starts a fake Zoom API seeded with the built-in Owner, Admin and Member roles.
+// Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		tokens:              map[string]time.Time{},
+		groupMembers:        map[string][]string{},
+		groupAdmins:         map[string][]string{},
+		roleMembers:         map[string][]string{},
+		contactGroupMembers: map[string][]zoom.GroupMember{},
+		features:            map[string]*zoom.UserFeatures{},
+		seats:               map[string]int{},
+		roles: []zoom.Role{
+			{ID: OwnerRoleID, Name: "Owner", Type: "common", Privileges: []string{"Account:Edit", "User:Edit", "Role:Edit"}},
+			{ID: AdminRoleID, Name: "Admin", Type: "common", Privileges: []string{"User:Edit", "Recording:Read"}},
+			{ID: MemberRoleID, Name: "Member", Type: "common"},
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /oauth/token", s.handleToken)
+
+	api := http.NewServeMux()
+	api.HandleFunc("GET /v2/users", s.listUsers)
+	api.HandleFunc("POST /v2/users", s.createUser)
+	api.HandleFunc("GET /v2/users/{userId}", s.getUser)
+	api.HandleFunc("PATCH /v2/users/{userId}", s.updateUser)
+	api.HandleFunc("DELETE /v2/users/{userId}", s.deleteUser)
+	api.HandleFunc("PUT /v2/users/{userId}/status", s.updateUserStatus)
+	api.HandleFunc("DELETE /v2/users/{userId}/token", s.revokeSSOToken)
+	api.HandleFunc("GET /v2/users/{userId}/settings", s.getUserSettings)
+	api.HandleFunc("PATCH /v2/users/{userId}/settings", s.updateUserSettings)
+	api.HandleFunc("GET /v2/groups", s.listGroups)
+	api.HandleFunc("GET /v2/groups/{groupId}/members", s.listGroupMembers(s.groupMembers, "members"))
+	api.HandleFunc("POST /v2/groups/{groupId}/members", s.addGroupMembers(s.groupMembers, "members", codeGroupMemberExists))
+	api.HandleFunc("DELETE /v2/groups/{groupId}/members/{userId}", s.deleteGroupMember(s.groupMembers, codeGroupMemberNotFound))
+	api.HandleFunc("GET /v2/groups/{groupId}/admins", s.listGroupMembers(s.groupAdmins, "admins"))
+	api.HandleFunc("POST /v2/groups/{groupId}/admins", s.addGroupMembers(s.groupAdmins, "admins", codeGroupAdminExists))
+	api.HandleFunc("DELETE /v2/groups/{groupId}/admins/{userId}", s.deleteGroupMember(s.groupAdmins, codeGroupAdminNotFound))
+	api.HandleFunc("GET /v2/roles", s.listRoles)
+	api.HandleFunc("POST /v2/roles", s.createRole)
+	api.HandleFunc("GET /v2/roles/{roleId}", s.getRole)
+	api.HandleFunc("PATCH /v2/roles/{roleId}", s.updateRole)
+	api.HandleFunc("DELETE /v2/roles/{roleId}", s.deleteRole)
+	api.HandleFunc("GET /v2/roles/{roleId}/members", s.listRoleMembers)
+	api.HandleFunc("POST /v2/roles/{roleId}/members", s.assignRole)
+	api.HandleFunc("DELETE /v2/roles/{roleId}/members/{userId}", s.unassignRole)
+	api.HandleFunc("GET /v2/contacts/groups", s.listContactGroups)
+	api.HandleFunc("GET /v2/contacts/groups/{groupId}/members", s.listContactGroupMembers)
+	mux.Handle("/v2/", s.authenticate(api))
+
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// Endpoints returns the endpoints to point a client or the connector at.
+func (s *Server) Endpoints() zoom.Endpoints {
+	return zoom.Endpoints{
+		APIBaseURL: s.URL + "/v2",
+		OAuthURL:   s.URL + "/oauth/token",
+	}
+}
+
+// NewClient returns a zoom.Client authenticated against the fake.
+func (s *Server) NewClient(opts ...zoom.ClientOption) *zoom.Client {
+	endpoints := s.Endpoints()
+	tokenSource := zoom.NewTokenSource(s.Client(), endpoints.OAuthURL, AccountID, ClientID, ClientSecret)
+
+	return zoom.NewClient(s.Client(), endpoints.APIBaseURL, tokenSource, opts...)
+}
+
+// AddUser adds an account user. Users without a role get the Member role, users
+// without a type are Basic and users without a status are active. The stored user is returned.
+func (s *Server) AddUser(user zoom.User) zoom.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user.ID == "" {
+		user.ID = s.newId("user")
+	}
+	if user.Status == "" {
+		user.Status = zoom.UserStatusActive
+	}
+	if user.Type == 0 {
+		user.Type = int(zoom.BasicUser)
+	}
+	if user.RoleID == "" {
+		user.RoleID = MemberRoleID
+	}
+	if role := s.findRole(user.RoleID); role != nil {
+		user.RoleName = role.Name
+	}
+
+	s.users = append(s.users, &user)
+
+	return user
+}
+
+// AddGroup adds a group, generating an ID if it has none.
+func (s *Server) AddGroup(group zoom.Group) zoom.Group {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if group.ID == "" {
+		group.ID = s.newId("group")
+	}
+	s.groups = append(s.groups, group)
+
+	return group
+}
+
+// AddGroupMember makes the user a member of the group.
+func (s *Server) AddGroupMember(groupId, userId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.groupMembers[groupId] = appendUnique(s.groupMembers[groupId], userId)
+}
+
+// AddGroupAdmin makes the user an admin of the group.
+func (s *Server) AddGroupAdmin(groupId, userId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.groupAdmins[groupId] = appendUnique(s.groupAdmins[groupId], userId)
+}
+
+// AddRole adds a custom role, generating an ID if it has none.
+func (s *Server) AddRole(role zoom.Role) zoom.Role {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if role.ID == "" {
+		role.ID = s.newId("role")
+	}
+	if role.Type == "" {
+		role.Type = "common"
+	}
+	s.roles = append(s.roles, role)
+
+	return role
+}
+
+// AddRoleMember gives the user an IQ or phone role, use AddUser's RoleID for common roles.
+func (s *Server) AddRoleMember(roleId, userId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.roleMembers[roleId] = appendUnique(s.roleMembers[roleId], userId)
+}
+
+// AddContactGroup adds a contact group, generating an ID if it has none.
+func (s *Server) AddContactGroup(group zoom.ContactGroup) zoom.ContactGroup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if group.ID == "" {
+		group.ID = s.newId("contact-group")
+	}
+	s.contactGroups = append(s.contactGroups, group)
+
+	return group
+}
+
+// AddContactGroupMember adds a user (type 1) or group (type 2) member to the contact group.
+func (s *Server) AddContactGroupMember(groupId string, member zoom.GroupMember) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.contactGroupMembers[groupId] = append(s.contactGroupMembers[groupId], member)
+}
+
+// User returns the current state of a user.
+func (s *Server) User(userId string) (zoom.User, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u := s.findUser(userId); u != nil {
+		return *u, true
+	}
+
+	return zoom.User{}, false
+}
+
+// SetUserFeatures sets the add-on plans of a user.
+func (s *Server) SetUserFeatures(userId string, features zoom.UserFeatures) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.features[userId] = &features
+}
+
+// UserFeatures returns the add-on plans of a user.
+func (s *Server) UserFeatures(userId string) zoom.UserFeatures {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f := s.features[userId]; f != nil {
+		return *f
+	}
+
+	return zoom.UserFeatures{}
+}
+
+// SetSeats limits the number of Licensed users (LicensedSeats) or users with an add-on, keyed by its feature name.
+func (s *Server) SetSeats(license string, seats int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seats[license] = seats
+}
+
+// GroupMemberIDs returns the IDs of the group's members.
+func (s *Server) GroupMemberIDs(groupId string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.groupMembers[groupId])
+}
+
+// GroupAdminIDs returns the IDs of the group's admins.
+func (s *Server) GroupAdminIDs(groupId string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.groupAdmins[groupId])
+}
+
+// FailNext makes the next API request (OAuth excluded) fail with the given Zoom error.
+// Calls queue up, each failure is used once.
+func (s *Server) FailNext(status int, code int, message string, header http.Header) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = append(s.failures, failure{status: status, code: code, message: message, header: header})
+}
+
+// RateLimitNext makes the next n API requests fail with a 429 of the given
+// X-RateLimit-Type ("QPS" or "Daily-limit") and an immediate Retry-After.
+func (s *Server) RateLimitNext(n int, rateLimitType string) {
+	for i := 0; i < n; i++ {
+		s.FailNext(
+			http.StatusTooManyRequests,
+			http.StatusTooManyRequests,
+			"You have reached the maximum per-second rate limit for this API. Try again later.",
+			http.Header{
+				"X-Ratelimit-Type":      []string{rateLimitType},
+				"X-Ratelimit-Remaining": []string{"0"},
+				"Retry-After":           []string{"0"},
+			},
+		)
+	}
+}
+
+// Requests returns every API request received so far as "METHOD /path".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.requests)
+}
+
+func (s *Server) newId(prefix string) string {
+	s.nextId++
+	return fmt.Sprintf("%s-%d", prefix, s.nextId)
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	clientId, clientSecret, ok := r.BasicAuth()
+	if !ok || clientId != ClientID || clientSecret != ClientSecret || r.URL.Query().Get("account_id") != AccountID {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"reason": "Invalid client_id or client_secret", "error": "invalid_client"})
+		return
+	}
+
+	s.mu.Lock()
+	token := s.newId("token")
+	s.tokens[token] = time.Now().Add(tokenTTL)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": token,
+		"token_type":   "bearer",
+		"expires_in":   int(tokenTTL.Seconds()),
+		"scope":        "user:read:admin group:read:admin role:read:admin",
+	})
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		expiresAt, ok := s.tokens[token]
+		if !ok || time.Now().After(expiresAt) {
+			s.mu.Unlock()
+			writeError(w, http.StatusUnauthorized, 124, "Invalid access token.")
+			return
+		}
+
+		if len(s.failures) > 0 {
+			f := s.failures[0]
+			s.failures = s.failures[1:]
+			s.mu.Unlock()
+
+			for k, v := range f.header {
+				w.Header()[k] = v
+			}
+			writeError(w, f.status, f.code, f.message)
+			return
+		}
+		s.mu.Unlock()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// paginate returns the page of items selected by the request's page_size and
+// next_page_token, in the shape of Zoom's list responses.
+func paginate[T any](w http.ResponseWriter, r *http.Request, key string, items []T) {
+	pageSize := defaultPageSize
+	if v := r.URL.Query().Get("page_size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, 300, "Invalid field: page_size.")
+			return
+		}
+		pageSize = min(n, zoom.MaxPageSize)
+	}
+
+	offset := 0
+	if token := r.URL.Query().Get("next_page_token"); token != "" {
+		n, err := strconv.Atoi(strings.TrimPrefix(token, "page-"))
+		if err != nil || !strings.HasPrefix(token, "page-") || n < 0 || n > len(items) {
+			writeError(w, http.StatusBadRequest, 300, "The next page token is invalid or expired.")
+			return
+		}
+		offset = n
+	}
+
+	end := min(offset+pageSize, len(items))
+	nextPageToken := ""
+	if end < len(items) {
+		nextPageToken = fmt.Sprintf("page-%d", end)
+	}
+
+	page := items[offset:end]
+	if page == nil {
+		page = []T{}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"next_page_token": nextPageToken,
+		"page_size":       pageSize,
+		"total_records":   len(items),
+		key:               page,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, code int, message string) {
+	writeJSON(w, status, map[string]any{"code": code, "message": message})
+}
+
+func appendUnique(ids []string, id string) []string {
+	if slices.Contains(ids, id) {
+		return ids
+	}
+	return append(ids, id)
+}
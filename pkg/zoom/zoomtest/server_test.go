@@ -0,0 +1,178 @@
+package zoomtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/conductorone/baton-zoom/pkg/zoom"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServerPaginatesLikeZoom(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	for i := 0; i < 7; i++ {
+		srv.AddUser(zoom.User{Email: fmt.Sprintf("user%d@example.com", i)})
+	}
+	srv.AddUser(zoom.User{Email: "pending@example.com", Status: "pending", CustomAttributes: []zoom.CustomAttribute{{Key: "cbf_1", Value: "R&D"}}})
+
+	c := srv.NewClient(zoom.WithPageSize(3))
+
+	page, err := c.GetUsers(context.Background(), "", "")
+	require.NoError(t, err)
+	require.Len(t, page.Items, 3)
+	require.Equal(t, 7, page.TotalRecords)
+	require.NotEmpty(t, page.NextPageToken)
+
+	users, err := c.Users("").Collect(context.Background())
+	require.NoError(t, err)
+	require.Len(t, users, 7)
+
+	pending, err := c.Users(zoom.UserStatusPending).Collect(context.Background())
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, "R&D", pending[0].CustomAttributes[0].Value)
+
+	_, err = c.GetUsers(context.Background(), "", "bogus")
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServerGroupAndRoleMembership(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	user := srv.AddUser(zoom.User{Email: "jane@example.com"})
+	group := srv.AddGroup(zoom.Group{Name: "Engineering"})
+	role := srv.AddRole(zoom.Role{Name: "Auditor"})
+
+	c := srv.NewClient()
+	ctx := context.Background()
+
+	require.NoError(t, c.AddGroupMembers(ctx, group.ID, user.ID))
+	require.Equal(t, []string{user.ID}, srv.GroupMemberIDs(group.ID))
+	require.True(t, zoom.IsAlreadyMember(c.AddGroupMembers(ctx, group.ID, user.ID)))
+	require.NoError(t, c.DeleteGroupMember(ctx, group.ID, user.ID))
+	require.Empty(t, srv.GroupMemberIDs(group.ID))
+	require.True(t, zoom.IsNotMember(c.DeleteGroupMember(ctx, group.ID, user.ID)))
+
+	require.NoError(t, c.AssignRole(ctx, role.ID, user.ID))
+	got, _ := srv.User(user.ID)
+	require.Equal(t, role.ID, got.RoleID)
+	require.True(t, zoom.IsAlreadyMember(c.AssignRole(ctx, role.ID, user.ID)))
+	require.NoError(t, c.UnassignRole(ctx, role.ID, user.ID))
+	got, _ = srv.User(user.ID)
+	require.Equal(t, MemberRoleID, got.RoleID)
+	require.True(t, zoom.IsNotMember(c.UnassignRole(ctx, role.ID, user.ID)))
+
+	err := c.AddGroupMembers(ctx, "missing", user.ID)
+	var apiErr *zoom.APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, 4130, apiErr.Code)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServerInjectsRateLimits(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.AddUser(zoom.User{Email: "jane@example.com"})
+	c := srv.NewClient()
+
+	srv.RateLimitNext(2, "QPS")
+	users, err := c.Users("").Collect(context.Background())
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	require.Len(t, srv.Requests(), 3)
+
+	srv.RateLimitNext(1, "Daily-limit")
+	_, err = c.GetUsers(context.Background(), "", "")
+	require.True(t, errors.Is(err, zoom.ErrDailyLimitExceeded))
+
+	srv.FailNext(http.StatusForbidden, 4711, "Invalid access token, does not contain scopes:[user:read:admin].", nil)
+	_, err = c.GetUsers(context.Background(), "", "")
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestServerCustomRoles(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	user := srv.AddUser(zoom.User{Email: "jane@example.com"})
+
+	c := srv.NewClient()
+	ctx := context.Background()
+
+	role, err := c.CreateRole(ctx, &zoom.RoleBody{Name: "Reviewer", Privileges: []string{"User:Read"}})
+	require.NoError(t, err)
+	require.Equal(t, zoom.RoleTypeCommon, role.Type)
+
+	require.NoError(t, c.UpdateRole(ctx, role.ID, &zoom.RoleBody{Privileges: []string{"User:Read", "User:Edit"}}))
+	got, err := c.GetRole(ctx, role.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"User:Read", "User:Edit"}, got.Privileges)
+
+	require.NoError(t, c.AssignRole(ctx, role.ID, user.ID))
+	require.Error(t, c.DeleteRole(ctx, role.ID))
+	require.NoError(t, c.AssignRole(ctx, MemberRoleID, user.ID))
+	require.NoError(t, c.DeleteRole(ctx, role.ID))
+
+	_, err = c.GetRole(ctx, role.ID)
+	require.Equal(t, codes.NotFound, status.Code(err))
+	require.Error(t, c.DeleteRole(ctx, AdminRoleID))
+}
+
+func TestServerLicenses(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	jane := srv.AddUser(zoom.User{Email: "jane@example.com"})
+	john := srv.AddUser(zoom.User{Email: "john@example.com"})
+	srv.SetSeats(LicensedSeats, 1)
+	srv.SetSeats("webinar", 1)
+
+	c := srv.NewClient()
+	ctx := context.Background()
+
+	require.NoError(t, c.UpdateUser(ctx, jane.ID, &zoom.UserUpdateBody{Type: zoom.LicensedUser}))
+	require.True(t, zoom.IsOutOfLicenses(c.UpdateUser(ctx, john.ID, &zoom.UserUpdateBody{Type: zoom.LicensedUser})))
+	got, _ := srv.User(jane.ID)
+	require.Equal(t, int(zoom.LicensedUser), got.Type)
+
+	require.NoError(t, c.UpdateUserFeatures(ctx, jane.ID, map[string]interface{}{"webinar": true, "webinar_capacity": 500}))
+	require.True(t, zoom.IsOutOfLicenses(c.UpdateUserFeatures(ctx, john.ID, map[string]interface{}{"webinar": true})))
+
+	settings, err := c.GetUserSettings(ctx, jane.ID)
+	require.NoError(t, err)
+	require.True(t, settings.Feature.Webinar)
+	require.Equal(t, 500, settings.Feature.WebinarCapacity)
+
+	require.NoError(t, c.UpdateUserFeatures(ctx, jane.ID, map[string]interface{}{"webinar": false}))
+	require.False(t, srv.UserFeatures(jane.ID).Webinar)
+	require.NoError(t, c.UpdateUserFeatures(ctx, john.ID, map[string]interface{}{"webinar": true}))
+}
+
+func TestServerDeprovisioning(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	jane := srv.AddUser(zoom.User{Email: "jane@example.com"})
+	john := srv.AddUser(zoom.User{Email: "john@example.com"})
+
+	c := srv.NewClient()
+	ctx := context.Background()
+
+	require.NoError(t, c.UpdateUserStatus(ctx, jane.ID, zoom.DeactivateUser))
+	got, _ := srv.User(jane.ID)
+	require.Equal(t, zoom.UserStatusInactive, got.Status)
+
+	require.Error(t, c.DeleteUser(ctx, jane.ID, zoom.DeleteUserOptions{TransferEmail: "nobody@example.com", TransferMeetings: true}))
+	require.NoError(t, c.DeleteUser(ctx, jane.ID, zoom.DeleteUserOptions{Action: zoom.DeleteUser, TransferEmail: john.Email, TransferMeetings: true}))
+	_, ok := srv.User(jane.ID)
+	require.False(t, ok)
+}